@@ -24,6 +24,13 @@ import (
 )
 
 func main() {
+	if isSubcommand, err := cmd.RunSubcommand(os.Args[1:]); isSubcommand {
+		if err != nil {
+			klog.Errorf("Failed to run %s:{%s}", os.Args[1], err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	if err := cmd.StartVolumeEventsController(); err != nil {
 		klog.Errorf("Failed to start volume event collector controller:{%s}", err.Error())
 		os.Exit(1)
@@ -3,14 +3,21 @@ module github.com/mayadata-io/volume-events-exporter
 go 1.16
 
 require (
+	github.com/Shopify/sarama v1.30.0
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/ghodss/yaml v1.0.0
-	github.com/google/go-cmp v0.5.4
+	github.com/google/go-cmp v0.5.5
 	github.com/gorilla/mux v1.8.0
+	github.com/minio/minio-go/v7 v7.0.14
+	github.com/nats-io/nats-server/v2 v2.6.5
+	github.com/nats-io/nats.go v1.13.1-0.20211018182449-f2416a8b1483
 	github.com/onsi/ginkgo v1.11.0
 	github.com/onsi/gomega v1.7.0
 	github.com/openebs/api/v2 v2.3.0
 	github.com/pkg/errors v0.9.1
+	github.com/xdg-go/scram v1.0.2
+	google.golang.org/grpc v1.40.0
+	google.golang.org/protobuf v1.27.1
 	k8s.io/api v0.21.3
 	k8s.io/apimachinery v0.21.3
 	k8s.io/client-go v0.21.3
@@ -0,0 +1,108 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/controller"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// runBackfill sends create events of the volumes which were provisioned
+// before volume events are enabled. It returns error if create event of
+// any volume couldn't be sent
+func runBackfill(args []string) error {
+	storageClasses := flag.String("storage-classes", "", "Comma separated names of StorageClasses whose volumes are backfilled. Volumes of all StorageClasses are backfilled if empty")
+	rate := flag.Float64("rate", 1, "Maximum number of create events sent per second")
+	if err := parseFlags(args); err != nil {
+		return err
+	}
+	if *rate <= 0 {
+		return errors.Errorf("rate must be greater than zero but got %v", *rate)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	pController, sinkCloser, err := newSyncedController(stopCh, true)
+	defer closeSink(sinkCloser)
+	if err != nil {
+		return err
+	}
+	backfiller, ok := pController.(controller.Backfiller)
+	if !ok {
+		return errors.Errorf("volume events controller doesn't support backfilling")
+	}
+
+	summary, err := backfiller.Backfill(controller.BackfillOptions{
+		StorageClasses: splitList(*storageClasses),
+		RateLimiter:    flowcontrol.NewTokenBucketRateLimiter(float32(*rate), 1),
+		DryRun:         *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	printBackfillSummary(os.Stdout, summary, *dryRun)
+	if len(summary.Failed) != 0 {
+		return errors.Errorf("create events of %d volumes are not sent", len(summary.Failed))
+	}
+	return nil
+}
+
+// printBackfillSummary writes the volumes whose create events are sent,
+// or would be sent in case of dry run
+func printBackfillSummary(w io.Writer, summary *controller.BackfillSummary, dryRun bool) {
+	if dryRun {
+		fmt.Fprintf(w, "Create events of %d volumes would be sent\n", len(summary.Volumes))
+	} else {
+		fmt.Fprintf(w, "Sent: %d, Failed: %d\n", len(summary.Volumes)-len(summary.Failed), len(summary.Failed))
+	}
+	for _, pvObj := range summary.Volumes {
+		if _, isFailed := summary.Failed[pvObj.Name]; isFailed {
+			continue
+		}
+		claim := "-"
+		if pvObj.Spec.ClaimRef != nil {
+			claim = pvObj.Spec.ClaimRef.Namespace + "/" + pvObj.Spec.ClaimRef.Name
+		}
+		fmt.Fprintf(w, "%s  %s  storageclass=%s claim=%s\n", backfillOutcome(dryRun), pvObj.Name, pvObj.Spec.StorageClassName, claim)
+	}
+	printVolumeErrors(w, "failed  ", summary.Failed)
+}
+
+func backfillOutcome(dryRun bool) string {
+	if dryRun {
+		return "pending"
+	}
+	return "sent   "
+}
+
+// splitList returns the non empty values of comma separated list
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
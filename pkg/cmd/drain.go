@@ -0,0 +1,82 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/controller"
+	"github.com/pkg/errors"
+)
+
+// runDrain delivers pending events of all the volumes and removes
+// event finalizers cluster wide. It returns error if events of
+// any volume couldn't be delivered
+func runDrain(args []string) error {
+	force := flag.Bool("force", false, "Removes event finalizers even if pending events couldn't be delivered, undelivered events are recorded as dropped")
+	if err := parseFlags(args); err != nil {
+		return err
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	pController, sinkCloser, err := newSyncedController(stopCh, true)
+	defer closeSink(sinkCloser)
+	if err != nil {
+		return err
+	}
+	drainer, ok := pController.(controller.Drainer)
+	if !ok {
+		return errors.Errorf("volume events controller doesn't support draining")
+	}
+
+	summary, err := drainer.Drain(*force)
+	if err != nil {
+		return err
+	}
+	printDrainSummary(os.Stdout, summary)
+	if !summary.IsComplete() {
+		return errors.Errorf("events of %d volumes are not delivered", len(summary.Dropped)+len(summary.Failed))
+	}
+	return nil
+}
+
+// printDrainSummary writes the outcome of draining each volume
+func printDrainSummary(w io.Writer, summary *controller.DrainSummary) {
+	fmt.Fprintf(w, "Drained: %d, Dropped: %d, Failed: %d\n", len(summary.Drained), len(summary.Dropped), len(summary.Failed))
+	for _, pvName := range summary.Drained {
+		fmt.Fprintf(w, "drained  %s\n", pvName)
+	}
+	printVolumeErrors(w, "dropped ", summary.Dropped)
+	printVolumeErrors(w, "failed  ", summary.Failed)
+}
+
+// printVolumeErrors writes the errors of volumes sorted by volume name
+func printVolumeErrors(w io.Writer, outcome string, volumeErrors map[string]error) {
+	pvNames := make([]string, 0, len(volumeErrors))
+	for pvName := range volumeErrors {
+		pvNames = append(pvNames, pvName)
+	}
+	sort.Strings(pvNames)
+	for _, pvName := range pvNames {
+		fmt.Fprintf(w, "%s %s: %v\n", outcome, pvName, volumeErrors[pvName])
+	}
+}
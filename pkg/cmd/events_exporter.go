@@ -19,12 +19,19 @@ package cmd
 import (
 	"context"
 	"flag"
+	"io"
 	"sync"
 
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
 	"github.com/mayadata-io/volume-events-exporter/pkg/controller"
+	"github.com/mayadata-io/volume-events-exporter/pkg/delivery"
+	"github.com/mayadata-io/volume-events-exporter/pkg/enrichment"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
 	"github.com/mayadata-io/volume-events-exporter/pkg/signals"
+	"github.com/mayadata-io/volume-events-exporter/pkg/webhook"
 	leader "github.com/openebs/api/v2/pkg/kubernetes/leaderelection"
 	"github.com/pkg/errors"
+	"k8s.io/client-go/dynamic"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -46,6 +53,11 @@ var (
 	generateK8sEvents       = flag.Bool("generate-k8s-events", false, "Enables generating Normal & Warning Kubernetes based events")
 	leaderElection          = flag.Bool("leader-election", false, "Enables leader election")
 	leaderElectionNamespace = flag.String("leader-election-namespace", "", "The namespace where the leader election resource exists. Defaults to the pod namespace if not set")
+	collectWorkloadContext  = flag.Bool("collect-workload-context", false, "Enables enriching create events with pods mounting the PVC and their owning workloads")
+	trackEventDelivery      = flag.Bool("track-event-delivery", false, "Enables recording delivery state of volume events in VolumeEventDelivery resources")
+	enableMutatingWebhook   = flag.Bool("enable-mutating-webhook", false, "Enables mutating webhook which injects event finalizers and opt-in annotation into NFS volume resources")
+	dryRun                  = flag.Bool("dry-run", false, "Renders volume events without sending them or updating volumes. Backfill command only lists the volumes whose create events would be sent")
+	dryRunOutput            = flag.String("dry-run-output", "", "Path of the file to which volume events rendered in dry run are appended as JSON Lines. Events are logged if empty")
 )
 
 const (
@@ -71,16 +83,73 @@ func StartVolumeEventsController() error {
 		return errors.Wrap(err, "error building kubernetes clientset")
 	}
 
+	if err := validateDryRun(); err != nil {
+		return err
+	}
+
+	// Configured sink is not connected in dry run
+	var eventsSenderBuilder collectorinterface.EventsSenderBuilder
+	var sinkCloser io.Closer
+	if *dryRun {
+		eventsSenderBuilder, sinkCloser, err = getDryRunSenderBuilder(*dryRunOutput)
+	} else {
+		eventsSenderBuilder, sinkCloser, err = getEventsSenderBuilder()
+	}
+	if err != nil {
+		return errors.Wrap(err, "error building events sink")
+	}
+	if sinkCloser != nil {
+		defer func() {
+			if err := sinkCloser.Close(); err != nil {
+				klog.Errorf("Failed to close events sink: %v", err)
+			}
+		}()
+	}
+
 	// NewSharedInformerFactory constructs a new instance of k8s sharedInformerFactory.
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, controller.GetSyncInterval())
 	pvInformer := kubeInformerFactory.Core().V1().PersistentVolumes()
 	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
-	pController := controller.NewPVEventController(kubeClient, pvInformer, pvcInformer, volumeEventControllerWorkers, *generateK8sEvents)
+	options, err := getControllerOptions(cfg, kubeInformerFactory)
+	if err != nil {
+		return err
+	}
+	options.EventsSenderBuilder = eventsSenderBuilder
+	stuckFinalizerPolicy := controller.StuckFinalizerPolicy(env.GetStuckFinalizerPolicy())
+	if !stuckFinalizerPolicy.IsValid() {
+		return errors.Errorf("unsupported stuck finalizer policy %q", stuckFinalizerPolicy)
+	}
+	options.StuckFinalizerPolicy = stuckFinalizerPolicy
+	options.StuckFinalizerThreshold = env.GetStuckFinalizerThreshold()
+	if *dryRun {
+		// Delivery of rendered events is not recorded
+		options.DeliveryTracker = nil
+		options.DryRun = true
+		klog.Infof("Running in dry run mode, volume events will only be rendered")
+	}
+	pController := controller.NewPVEventController(kubeClient, pvInformer, pvcInformer, volumeEventControllerWorkers, *generateK8sEvents, options)
 
 	// set up signals so we handle the first shutdown signal gracefully
 	stopCh := signals.SetupSignalHandler()
 	var wg sync.WaitGroup
 
+	// Webhook is served by all the replicas irrespective of leader election
+	if *enableMutatingWebhook {
+		webhookServer := webhook.NewServer()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancelFn := context.WithCancel(context.TODO())
+			go func() {
+				<-stopCh
+				cancelFn()
+			}()
+			if err := webhookServer.Run(ctx); err != nil {
+				klog.Errorf("Mutating webhook server stopped: %v", err)
+			}
+		}()
+	}
+
 	run := func(ctx context.Context) {
 
 		// Start registered informers
@@ -116,6 +185,69 @@ func StartVolumeEventsController() error {
 	return nil
 }
 
+// validateDryRun returns error if features which update volume resources
+// are enabled along with dry run
+func validateDryRun() error {
+	if !*dryRun {
+		return nil
+	}
+	if *enableMutatingWebhook {
+		return errors.Errorf("mutating webhook can't be enabled in dry run")
+	}
+	if controller.StuckFinalizerPolicy(env.GetStuckFinalizerPolicy()) == controller.StuckFinalizerPolicyRelease {
+		return errors.Errorf("stuck finalizer policy %q can't be used in dry run", controller.StuckFinalizerPolicyRelease)
+	}
+	return nil
+}
+
+// getControllerOptions returns the resolvers and delivery tracker of
+// controller enabled via command line flags and environment variables
+func getControllerOptions(cfg *rest.Config, kubeInformerFactory kubeinformers.SharedInformerFactory) (controller.Options, error) {
+	resolvers, err := getResolvers(kubeInformerFactory)
+	if err != nil {
+		return controller.Options{}, errors.Wrap(err, "error building event resolvers")
+	}
+	var deliveryTracker *delivery.Tracker
+	if *trackEventDelivery {
+		dynamicClient, err := dynamic.NewForConfig(cfg)
+		if err != nil {
+			return controller.Options{}, errors.Wrap(err, "error building dynamic client")
+		}
+		deliveryTracker = delivery.NewTracker(dynamicClient, getEventsSinkName())
+	}
+	return controller.Options{
+		Resolvers:       resolvers,
+		DeliveryTracker: deliveryTracker,
+	}, nil
+}
+
+// getResolvers returns the resolvers enabled via command line flags and
+// environment variables. Informers required by resolvers are registered
+// on given factory
+func getResolvers(kubeInformerFactory kubeinformers.SharedInformerFactory) (enrichment.Resolvers, error) {
+	resolvers := enrichment.Resolvers{
+		StorageClass: enrichment.NewStorageClassResolver(kubeInformerFactory.Storage().V1().StorageClasses()),
+	}
+	if *collectWorkloadContext {
+		workloadResolver, err := enrichment.NewWorkloadResolver(
+			kubeInformerFactory.Core().V1().Pods(),
+			kubeInformerFactory.Apps().V1().ReplicaSets(),
+			kubeInformerFactory.Apps().V1().StatefulSets())
+		if err != nil {
+			return resolvers, err
+		}
+		resolvers.Workload = workloadResolver
+	}
+	if tenantFields := env.GetTenantFields(); len(tenantFields) != 0 {
+		tenantResolver, err := enrichment.NewTenantResolver(kubeInformerFactory.Core().V1().Namespaces(), tenantFields)
+		if err != nil {
+			return resolvers, err
+		}
+		resolvers.Tenant = tenantResolver
+	}
+	return resolvers, nil
+}
+
 // getClusterConfig return the config for k8s.
 func getClusterConfig(kubeconfig string) (*rest.Config, error) {
 	if kubeconfig != "" {
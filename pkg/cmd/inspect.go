@@ -0,0 +1,212 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/controller"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/pkg/errors"
+)
+
+// runStatus prints the delivery state of events of a volume
+func runStatus(args []string) error {
+	pvName, err := parseVolumeFlags(args)
+	if err != nil {
+		return err
+	}
+	inspector, closeFn, err := newInspector(false)
+	defer closeFn()
+	if err != nil {
+		return err
+	}
+	status, err := inspector.GetVolumeStatus(pvName)
+	if err != nil {
+		return err
+	}
+	printVolumeStatus(os.Stdout, status)
+	return nil
+}
+
+// runPending lists the volumes awaiting delivery of events
+func runPending(args []string) error {
+	if err := parseFlags(args); err != nil {
+		return err
+	}
+	inspector, closeFn, err := newInspector(false)
+	defer closeFn()
+	if err != nil {
+		return err
+	}
+	pendingVolumes, err := inspector.ListPendingVolumes()
+	if err != nil {
+		return err
+	}
+	printPendingVolumes(os.Stdout, pendingVolumes)
+	return nil
+}
+
+// runRender prints the payload of an event of a volume as
+// sent to the configured sink, without sending it
+func runRender(args []string) error {
+	eventType := flag.String("event", string(collectorinterface.CreateEventType), "Type of the event to render, create or delete")
+	pvName, err := parseVolumeFlags(args)
+	if err != nil {
+		return err
+	}
+	if err := validateEventType(*eventType); err != nil {
+		return err
+	}
+	transformers, err := getPayloadTransformers(env.GetEventsSinkType())
+	if err != nil {
+		return err
+	}
+	inspector, closeFn, err := newInspector(false)
+	defer closeFn()
+	if err != nil {
+		return err
+	}
+
+	metadata, data, err := inspector.Render(pvName, collectorinterface.EventType(*eventType))
+	if err != nil {
+		return err
+	}
+	for _, transformer := range transformers {
+		data, err = transformer.Transform(metadata, data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to transform %s event data of volume %s", metadata.EventType, pvName)
+		}
+	}
+	fmt.Fprintln(os.Stdout, data)
+	return nil
+}
+
+// runResend delivers an event of a volume to the configured
+// sink once again
+func runResend(args []string) error {
+	eventType := flag.String("event", string(collectorinterface.CreateEventType), "Type of the event to resend, create or delete")
+	pvName, err := parseVolumeFlags(args)
+	if err != nil {
+		return err
+	}
+	if err := validateEventType(*eventType); err != nil {
+		return err
+	}
+	inspector, closeFn, err := newInspector(true)
+	defer closeFn()
+	if err != nil {
+		return err
+	}
+	err = inspector.Resend(pvName, collectorinterface.EventType(*eventType))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "Resent %s event of volume %s\n", *eventType, pvName)
+	return nil
+}
+
+// newInspector returns the inspector of volume events. Returned
+// func must be called once subcommand is done, even on error
+func newInspector(withSink bool) (controller.Inspector, func(), error) {
+	stopCh := make(chan struct{})
+	pController, sinkCloser, err := newSyncedController(stopCh, withSink)
+	closeFn := func() {
+		close(stopCh)
+		closeSink(sinkCloser)
+	}
+	if err != nil {
+		return nil, closeFn, err
+	}
+	inspector, ok := pController.(controller.Inspector)
+	if !ok {
+		return nil, closeFn, errors.Errorf("volume events controller doesn't support inspecting events")
+	}
+	return inspector, closeFn, nil
+}
+
+func validateEventType(eventType string) error {
+	switch collectorinterface.EventType(eventType) {
+	case collectorinterface.CreateEventType, collectorinterface.DeleteEventType:
+		return nil
+	}
+	return errors.Errorf("unsupported event type %q, supported types are create and delete", eventType)
+}
+
+// printVolumeStatus writes the delivery state of events of a volume
+func printVolumeStatus(w io.Writer, status *controller.VolumeStatus) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "PV:\t%s\n", status.PVName)
+	fmt.Fprintf(tw, "UID:\t%s\n", status.PVUID)
+	fmt.Fprintf(tw, "CAS Type:\t%s\n", status.CASType)
+	fmt.Fprintf(tw, "Events Required:\t%t\n", status.IsEventRequired)
+	if status.Origin != "" {
+		fmt.Fprintf(tw, "Origin:\t%s\n", status.Origin)
+	}
+	fmt.Fprintf(tw, "Phase:\t%s\n", status.Phase)
+	fmt.Fprintf(tw, "Event Finalizer:\t%t\n", status.HasEventFinalizer)
+	if deliveryStatus := status.DeliveryStatus; deliveryStatus != nil {
+		fmt.Fprintf(tw, "Attempts:\t%d\n", deliveryStatus.Attempts)
+		if deliveryStatus.LastAttemptTime != nil {
+			fmt.Fprintf(tw, "Last Attempt:\t%s\n", deliveryStatus.LastAttemptTime.UTC().Format(timeFormat))
+		}
+		if deliveryStatus.LastError != "" {
+			fmt.Fprintf(tw, "Last Error:\t%s\n", deliveryStatus.LastError)
+		}
+		if deliveryStatus.NextRetryTime != nil {
+			fmt.Fprintf(tw, "Next Retry:\t%s\n", deliveryStatus.NextRetryTime.UTC().Format(timeFormat))
+		}
+	}
+	for _, eventDelivery := range status.Deliveries {
+		deliveryStatus := eventDelivery.Status
+		fmt.Fprintf(tw, "Delivery %s:\t%s, %d attempts", eventDelivery.Spec.EventType, deliveryStatus.Phase, deliveryStatus.Attempts)
+		if deliveryStatus.SentTime != nil {
+			fmt.Fprintf(tw, ", sent at %s", deliveryStatus.SentTime.UTC().Format(timeFormat))
+		}
+		if deliveryStatus.LastError != "" {
+			fmt.Fprintf(tw, ", last error: %s", deliveryStatus.LastError)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
+
+// printPendingVolumes writes the volumes awaiting delivery of events as a table
+func printPendingVolumes(w io.Writer, pendingVolumes []*controller.VolumeStatus) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tPHASE\tATTEMPTS\tNEXT RETRY\tLAST ERROR")
+	for _, status := range pendingVolumes {
+		attempts, nextRetry, lastError := "0", "-", "-"
+		if deliveryStatus := status.DeliveryStatus; deliveryStatus != nil {
+			attempts = strconv.Itoa(deliveryStatus.Attempts)
+			if deliveryStatus.NextRetryTime != nil {
+				nextRetry = deliveryStatus.NextRetryTime.UTC().Format(timeFormat)
+			}
+			if deliveryStatus.LastError != "" {
+				lastError = deliveryStatus.LastError
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", status.PVName, status.Phase, attempts, nextRetry, lastError)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/mayadata-io/volume-events-exporter/pkg/payload"
+	"github.com/pkg/errors"
+)
+
+// typedSinkTypes are the sinks which decode the collected data and
+// hence payload can't be transformed into custom schema or shape
+var typedSinkTypes = map[string]bool{
+	grpcSinkType:    true,
+	chatOpsSinkType: true,
+}
+
+// getPayloadTransformers returns the transformers configured via environment
+// variables for given sink. Collected data is filtered first, then converted
+// into configured schema and then rendered using template if configured.
+// Templates are validated here so that invalid configuration fails the
+// startup instead of delivery of every event
+func getPayloadTransformers(sinkType string) ([]payload.Transformer, error) {
+	if sinkType == "" {
+		sinkType = tokenAuthSinkType
+	}
+	filter, err := payload.NewFilter(payload.FilterConfig{
+		KeepManagedFields:   env.IsPayloadKeepManagedFieldsEnabled(),
+		LabelAllowList:      env.GetPayloadLabelAllowList(),
+		LabelDenyList:       env.GetPayloadLabelDenyList(),
+		AnnotationAllowList: env.GetPayloadAnnotationAllowList(),
+		AnnotationDenyList:  env.GetPayloadAnnotationDenyList(),
+		RedactFields:        env.GetPayloadRedactFields(),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build payload filter")
+	}
+	transformers := []payload.Transformer{filter}
+
+	// Schema configured for the sink(ex: PAYLOAD_SCHEMA_KAFKA) takes
+	// precedence over the schema configured for all sinks
+	schema := env.GetSinkPayloadSchema(sinkType)
+	if schema == "" {
+		schema = env.GetPayloadSchema()
+	}
+	switch schema {
+	case payload.RawSchema:
+	case payload.CompactSchema:
+		if typedSinkTypes[sinkType] {
+			return nil, errors.Errorf("%s payload schema is not supported by %s sink", schema, sinkType)
+		}
+		transformers = append(transformers, payload.NewCompactSchemaTransformer())
+	default:
+		return nil, errors.Errorf("unsupported payload schema %q", schema)
+	}
+
+	templateTransformer, err := getPayloadTemplate(sinkType)
+	if err != nil {
+		return nil, err
+	}
+	if templateTransformer != nil {
+		transformers = append(transformers, templateTransformer)
+	}
+	return transformers, nil
+}
+
+// getPayloadTemplate returns the payload template configured
+// for given sink, nil if template is not configured. Template
+// configured for the sink(ex: PAYLOAD_TEMPLATE_FILE_KAFKA) takes
+// precedence over the template configured for all sinks
+func getPayloadTemplate(sinkType string) (payload.Transformer, error) {
+	templateFileEnv := env.SinkEnvName(env.PayloadTemplateFile, sinkType)
+	jsonPathEnv := env.SinkEnvName(env.PayloadJSONPath, sinkType)
+	templateFile := env.GetSinkPayloadTemplateFile(sinkType)
+	jsonPath := env.GetSinkPayloadJSONPath(sinkType)
+	if templateFile == "" && jsonPath == "" {
+		templateFileEnv, jsonPathEnv = env.PayloadTemplateFile, env.PayloadJSONPath
+		templateFile, jsonPath = env.GetPayloadTemplateFile(), env.GetPayloadJSONPath()
+	}
+	if templateFile == "" && jsonPath == "" {
+		return nil, nil
+	}
+	if templateFile != "" && jsonPath != "" {
+		return nil, errors.Errorf("only one of %s and %s can be set", templateFileEnv, jsonPathEnv)
+	}
+	if typedSinkTypes[sinkType] {
+		return nil, errors.Errorf("payload templates are not supported by %s sink", sinkType)
+	}
+
+	if jsonPath != "" {
+		jsonPathTemplate, err := payload.NewJSONPathTemplate(jsonPath)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPathTemplate, nil
+	}
+
+	content, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read payload template %s", templateFile)
+	}
+	goTemplate, err := payload.NewGoTemplate(string(content))
+	if err != nil {
+		return nil, err
+	}
+	return goTemplate, nil
+}
@@ -0,0 +1,34 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/payload"
+)
+
+// runSchema prints the JSON Schema document of compact payload so
+// that receivers can validate the events sent with compact schema
+func runSchema(args []string) error {
+	if err := parseFlags(args); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(os.Stdout, payload.CompactSchemaJSON)
+	return err
+}
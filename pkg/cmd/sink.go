@@ -0,0 +1,145 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/chatops"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/dryrun"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/exechook"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/grpcclient"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/jsonlines"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/kafka"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/nats"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/s3"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/tokenauth"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/mayadata-io/volume-events-exporter/pkg/payload"
+	"github.com/pkg/errors"
+)
+
+const (
+	// tokenAuthSinkType sends events to REST server using token based authentication
+	tokenAuthSinkType = "tokenauth"
+	// kafkaSinkType publishes events to Kafka topics
+	kafkaSinkType = "kafka"
+	// natsSinkType publishes events to NATS JetStream
+	natsSinkType = "nats"
+	// grpcSinkType publishes typed events to gRPC server
+	grpcSinkType = "grpc"
+	// fileSinkType appends events to a local file as JSON Lines
+	fileSinkType = "file"
+	// execSinkType pipes events to a local executable
+	execSinkType = "exec"
+	// s3SinkType writes events as objects to S3 compatible storage
+	s3SinkType = "s3"
+	// chatOpsSinkType posts events as messages to chat incoming webhooks
+	chatOpsSinkType = "chatops"
+)
+
+// getEventsSenderBuilder returns the builder of events sender for the sink
+// configured via environment variable. Returned closer(can be nil) must be
+// closed once the controller is stopped to release sink resources
+func getEventsSenderBuilder() (collectorinterface.EventsSenderBuilder, io.Closer, error) {
+	sinkType := env.GetEventsSinkType()
+	transformers, err := getPayloadTransformers(sinkType)
+	if err != nil {
+		return nil, nil, err
+	}
+	builder, closer, err := newEventsSenderBuilder(sinkType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return payload.NewTransformingSenderBuilder(builder, transformers...), closer, nil
+}
+
+// getDryRunSenderBuilder returns the builder of events sender which renders
+// payloads of the configured sink to logs, or to the file at given path
+// if it is not empty, instead of sending them
+func getDryRunSenderBuilder(path string) (collectorinterface.EventsSenderBuilder, io.Closer, error) {
+	transformers, err := getPayloadTransformers(env.GetEventsSinkType())
+	if err != nil {
+		return nil, nil, err
+	}
+	sink, err := dryrun.NewSink(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return payload.NewTransformingSenderBuilder(sink.NewDryRunClient, transformers...), sink, nil
+}
+
+// newEventsSenderBuilder returns the builder of events sender for given sink type
+func newEventsSenderBuilder(sinkType string) (collectorinterface.EventsSenderBuilder, io.Closer, error) {
+	switch sinkType {
+	case "", tokenAuthSinkType:
+		return tokenauth.NewTokenClient, nil, nil
+	case kafkaSinkType:
+		producer, err := kafka.NewProducer()
+		if err != nil {
+			return nil, nil, err
+		}
+		return producer.NewKafkaClient, producer, nil
+	case natsSinkType:
+		publisher, err := nats.NewPublisher()
+		if err != nil {
+			return nil, nil, err
+		}
+		return publisher.NewNATSClient, publisher, nil
+	case grpcSinkType:
+		connection, err := grpcclient.NewConnection()
+		if err != nil {
+			return nil, nil, err
+		}
+		return connection.NewGRPCClient, connection, nil
+	case fileSinkType:
+		writer, err := jsonlines.NewFileWriter()
+		if err != nil {
+			return nil, nil, err
+		}
+		return writer.NewFileClient, writer, nil
+	case execSinkType:
+		hook, err := exechook.NewHook()
+		if err != nil {
+			return nil, nil, err
+		}
+		return hook.NewExecClient, nil, nil
+	case s3SinkType:
+		uploader, err := s3.NewUploader()
+		if err != nil {
+			return nil, nil, err
+		}
+		return uploader.NewS3Client, nil, nil
+	case chatOpsSinkType:
+		notifier, err := chatops.NewNotifier()
+		if err != nil {
+			return nil, nil, err
+		}
+		return notifier.NewChatOpsClient, nil, nil
+	}
+	return nil, nil, errors.Errorf("unsupported events sink type %q", sinkType)
+}
+
+// getEventsSinkName returns the type of configured sink, it is
+// recorded as destination of delivered events
+func getEventsSinkName() string {
+	if sinkType := env.GetEventsSinkType(); sinkType != "" {
+		return sinkType
+	}
+	return tokenAuthSinkType
+}
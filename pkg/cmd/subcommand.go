@@ -0,0 +1,159 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/controller"
+	"github.com/pkg/errors"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// drainCommand delivers pending events and removes event finalizers
+	drainCommand = "drain"
+	// backfillCommand sends create events of existing volumes
+	backfillCommand = "backfill"
+	// statusCommand shows the delivery state of events of a volume
+	statusCommand = "status"
+	// pendingCommand lists the volumes awaiting delivery of events
+	pendingCommand = "pending"
+	// renderCommand prints the payload of an event of a volume
+	renderCommand = "render"
+	// resendCommand delivers an event of a volume once again
+	resendCommand = "resend"
+	// schemaCommand prints the JSON Schema of compact payload
+	schemaCommand = "schema"
+
+	// timeFormat is the format of times printed by subcommands
+	timeFormat = time.RFC3339
+)
+
+// subcommands are the one-shot modes of the exporter which
+// are run as "volume-events-exporter <subcommand> [flags]"
+var subcommands = map[string]func(args []string) error{
+	drainCommand:    runDrain,
+	backfillCommand: runBackfill,
+	statusCommand:   runStatus,
+	pendingCommand:  runPending,
+	renderCommand:   runRender,
+	resendCommand:   runResend,
+	schemaCommand:   runSchema,
+}
+
+// RunSubcommand runs the subcommand named by the first argument with
+// rest of the arguments as flags. It returns false if the first argument
+// is not a subcommand, volume events controller is started in that case
+func RunSubcommand(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	run, isExist := subcommands[args[0]]
+	if !isExist {
+		return false, nil
+	}
+	return true, run(args[1:])
+}
+
+// parseFlags parses the flags of subcommand along with klog flags
+func parseFlags(args []string) error {
+	klog.InitFlags(nil)
+	return flag.CommandLine.Parse(args)
+}
+
+// parseVolumeFlags parses the flags of subcommand which operates on
+// a volume. Name of the volume can be given before or after the flags
+func parseVolumeFlags(args []string) (string, error) {
+	var pvName string
+	if len(args) != 0 && !strings.HasPrefix(args[0], "-") {
+		pvName, args = args[0], args[1:]
+	}
+	if err := parseFlags(args); err != nil {
+		return "", err
+	}
+	remainingArgs := flag.Args()
+	if pvName == "" && len(remainingArgs) != 0 {
+		pvName, remainingArgs = remainingArgs[0], remainingArgs[1:]
+	}
+	if pvName == "" {
+		return "", errors.Errorf("name of the PV is required")
+	}
+	if len(remainingArgs) != 0 {
+		return "", errors.Errorf("unexpected arguments %v", remainingArgs)
+	}
+	return pvName, nil
+}
+
+// newSyncedController returns the volume events controller used by
+// subcommands once informers are synced. Controller is not started
+// hence volumes are reconciled only when subcommand invokes it.
+// Configured events sink is built only if withSink is true, returned
+// closer(can be nil) must be closed once subcommand is done
+func newSyncedController(stopCh <-chan struct{}, withSink bool) (controller.Controller, io.Closer, error) {
+	cfg, err := getClusterConfig(*kubeconfig)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error building kubeconfig")
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error building kubernetes clientset")
+	}
+
+	var eventsSenderBuilder collectorinterface.EventsSenderBuilder
+	var sinkCloser io.Closer
+	if withSink {
+		eventsSenderBuilder, sinkCloser, err = getEventsSenderBuilder()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "error building events sink")
+		}
+	}
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 0)
+	pvInformer := kubeInformerFactory.Core().V1().PersistentVolumes()
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	options, err := getControllerOptions(cfg, kubeInformerFactory)
+	if err != nil {
+		return nil, sinkCloser, err
+	}
+	options.EventsSenderBuilder = eventsSenderBuilder
+	pController := controller.NewPVEventController(kubeClient, pvInformer, pvcInformer, volumeEventControllerWorkers, *generateK8sEvents, options)
+
+	kubeInformerFactory.Start(stopCh)
+	for informerType, isSynced := range kubeInformerFactory.WaitForCacheSync(stopCh) {
+		if !isSynced {
+			return nil, sinkCloser, errors.Errorf("timed out waiting for %v cache to sync", informerType)
+		}
+	}
+	return pController, sinkCloser, nil
+}
+
+// closeSink releases the resources of events sink
+func closeSink(sinkCloser io.Closer) {
+	if sinkCloser == nil {
+		return
+	}
+	if err := sinkCloser.Close(); err != nil {
+		klog.Errorf("Failed to close events sink: %v", err)
+	}
+}
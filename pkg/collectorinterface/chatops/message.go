@@ -0,0 +1,103 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chatops
+
+import (
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MessageData is the data against which message template is rendered
+type MessageData struct {
+	EventType collectorinterface.EventType
+	PVName    string
+	PVUID     string
+	CASType   string
+
+	// PVCNamespace and PVCName are empty if claim doesn't exist
+	PVCNamespace string
+	PVCName      string
+
+	Capacity     string
+	StorageClass string
+
+	// BackingPVCNamespace, BackingPVCName and BackingPVName
+	// identifies the volume backing the NFS volume
+	BackingPVCNamespace string
+	BackingPVCName      string
+	BackingPVName       string
+
+	CreatedAt time.Time
+	// DeletedAt and Lifetime are set only for delete events
+	DeletedAt time.Time
+	Lifetime  time.Duration
+}
+
+// newMessageData builds the message data from serialized
+// data collected by collector of given CAS type
+func newMessageData(metadata collectorinterface.EventMetadata, data string) (*MessageData, error) {
+	messageData := &MessageData{
+		EventType: metadata.EventType,
+		PVName:    metadata.PVName,
+		PVUID:     metadata.PVUID,
+		CASType:   metadata.CASType,
+	}
+
+	switch metadata.CASType {
+	case nfspv.OpenEBSNFSCASLabelValue:
+		volumeData, _, err := nfspv.UnmarshalNFSVolumeData(metadata.EventType, data)
+		if err != nil {
+			return nil, err
+		}
+		messageData.fillNFSVolumeData(volumeData)
+	default:
+		return nil, errors.Errorf("rendering message of %s volume is not supported", metadata.CASType)
+	}
+	return messageData, nil
+}
+
+func (m *MessageData) fillNFSVolumeData(volumeData *nfspv.NFSVolumeData) {
+	pv := volumeData.NFSPV
+	m.StorageClass = pv.Spec.StorageClassName
+	if capacity, isExist := pv.Spec.Capacity[corev1.ResourceStorage]; isExist {
+		m.Capacity = capacity.String()
+	}
+	m.CreatedAt = pv.CreationTimestamp.UTC()
+	if pv.DeletionTimestamp != nil {
+		m.DeletedAt = pv.DeletionTimestamp.UTC()
+		m.Lifetime = m.DeletedAt.Sub(m.CreatedAt)
+	}
+
+	if volumeData.NFSPVC != nil {
+		m.PVCNamespace = volumeData.NFSPVC.Namespace
+		m.PVCName = volumeData.NFSPVC.Name
+	} else if pv.Spec.ClaimRef != nil {
+		m.PVCNamespace = pv.Spec.ClaimRef.Namespace
+		m.PVCName = pv.Spec.ClaimRef.Name
+	}
+	if volumeData.BackingPVC != nil {
+		m.BackingPVCNamespace = volumeData.BackingPVC.Namespace
+		m.BackingPVCName = volumeData.BackingPVC.Name
+	}
+	if volumeData.BackingPV != nil {
+		m.BackingPVName = volumeData.BackingPV.Name
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chatops
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+)
+
+const (
+	slackPlatform      = "slack"
+	mattermostPlatform = "mattermost"
+	teamsPlatform      = "teams"
+)
+
+// platform describes the formatting and payload
+// shape of incoming webhooks of a chat platform
+type platform struct {
+	// bold returns the text formatted in bold
+	bold func(text string) string
+	// newPayload returns the webhook payload for given message
+	newPayload func(message, summary string) interface{}
+}
+
+// textPayload is the payload of Slack and Mattermost incoming webhooks
+type textPayload struct {
+	Text string `json:"text"`
+}
+
+// messageCardPayload is the payload of Microsoft Teams incoming webhooks
+type messageCardPayload struct {
+	Type    string `json:"@type"`
+	Context string `json:"@context"`
+	Summary string `json:"summary"`
+	Text    string `json:"text"`
+}
+
+var platforms = map[string]platform{
+	slackPlatform: {
+		// Slack mrkdwn uses single asterisk for bold
+		bold: func(text string) string { return "*" + text + "*" },
+		newPayload: func(message, _ string) interface{} {
+			return textPayload{Text: message}
+		},
+	},
+	mattermostPlatform: {
+		bold: func(text string) string { return "**" + text + "**" },
+		newPayload: func(message, _ string) interface{} {
+			return textPayload{Text: message}
+		},
+	},
+	teamsPlatform: {
+		bold: func(text string) string { return "**" + text + "**" },
+		newPayload: func(message, summary string) interface{} {
+			return messageCardPayload{
+				Type:    "MessageCard",
+				Context: "https://schema.org/extensions",
+				Summary: summary,
+				Text:    message,
+			}
+		},
+	},
+}
+
+// defaultTemplate renders the volume details as Markdown list
+const defaultTemplate = `{{ bold (printf "Volume %s %s" .PVName (action .EventType)) }}
+- PVC: {{ if .PVCName }}{{ .PVCNamespace }}/{{ .PVCName }}{{ else }}-{{ end }}
+- Capacity: {{ or .Capacity "-" }}
+- StorageClass: {{ or .StorageClass "-" }}
+- Backing volume: {{ if .BackingPVName }}{{ .BackingPVName }}{{ if .BackingPVCName }} ({{ .BackingPVCNamespace }}/{{ .BackingPVCName }}){{ end }}{{ else }}-{{ end }}
+{{- if eq .EventType "delete" }}
+- Lifetime: {{ duration .Lifetime }}
+{{- end }}`
+
+// newTemplate parses the given message template, functions bold,
+// action and duration are available to the template
+func newTemplate(p platform, text string) (*template.Template, error) {
+	return template.New("message").Funcs(template.FuncMap{
+		"bold":     p.bold,
+		"action":   action,
+		"duration": duration,
+	}).Parse(text)
+}
+
+// action returns the past tense form of event type
+func action(eventType collectorinterface.EventType) string {
+	switch eventType {
+	case collectorinterface.CreateEventType:
+		return "provisioned"
+	case collectorinterface.DeleteEventType:
+		return "deleted"
+	}
+	return string(eventType)
+}
+
+// duration returns the human readable form of duration ex: 2d 3h4m5s
+func duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	if days == 0 {
+		return d.String()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%dd", days)
+	if remaining := d - days*24*time.Hour; remaining > 0 {
+		fmt.Fprintf(&b, " %s", remaining)
+	}
+	return b.String()
+}
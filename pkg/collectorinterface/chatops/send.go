@@ -0,0 +1,151 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chatops
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Notifier posts messages to incoming webhook of chat
+// platform. It is shared across all the ChatOpsClient instances
+type Notifier struct {
+	webhookURL string
+
+	platform platform
+
+	// template renders the message from MessageData
+	template *template.Template
+
+	client *http.Client
+}
+
+// ChatOpsClient posts volume events as human readable messages
+type ChatOpsClient struct {
+	*Notifier
+	// VolumeCollector implements methods required for event collector
+	collectorinterface.VolumeEventCollector
+}
+
+// NewNotifier returns the notifier configured via environment variables
+func NewNotifier() (*Notifier, error) {
+	webhookURL := env.GetChatOpsWebhookURL()
+	if webhookURL == "" {
+		return nil, errors.Errorf("webhook URL is not configured, set %s", env.ChatOpsWebhookURL)
+	}
+
+	templateText := defaultTemplate
+	if templateFile := env.GetChatOpsTemplateFile(); templateFile != "" {
+		content, err := ioutil.ReadFile(templateFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read message template %s", templateFile)
+		}
+		templateText = string(content)
+	}
+	return newNotifier(webhookURL, env.GetChatOpsPlatform(), templateText, env.GetChatOpsTimeout())
+}
+
+func newNotifier(webhookURL, platformName, templateText string, timeout time.Duration) (*Notifier, error) {
+	p, isExist := platforms[platformName]
+	if !isExist {
+		return nil, errors.Errorf("unsupported chat platform %q", platformName)
+	}
+	tmpl, err := newTemplate(p, templateText)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse message template")
+	}
+	// Render sample data so that references to unknown
+	// fields are reported at startup instead of on events
+	if err := tmpl.Execute(ioutil.Discard, &MessageData{EventType: collectorinterface.DeleteEventType}); err != nil {
+		return nil, errors.Wrapf(err, "failed to validate message template")
+	}
+	return &Notifier{
+		webhookURL: webhookURL,
+		platform:   p,
+		template:   tmpl,
+		client:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// NewChatOpsClient returns events sender which posts events
+// collected by given collector as messages
+func (n *Notifier) NewChatOpsClient(collectorInterface collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	return &ChatOpsClient{
+		Notifier:             n,
+		VolumeEventCollector: collectorInterface,
+	}
+}
+
+// Send renders the message from data and posts it to webhook.
+// Event is treated as delivered only on 2xx response
+func (c *ChatOpsClient) Send(metadata collectorinterface.EventMetadata, data string) error {
+	dataType := c.GetDataType()
+	if dataType != collectorinterface.JSONDataType {
+		return errors.Errorf("unsupported data type %s", dataType)
+	}
+
+	payload, err := c.renderPayload(metadata, data)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrapf(err, "failed to build webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// NOTE: Error is not wrapped with URL since webhook URL holds the secret
+		return errors.Errorf("failed to post %s event of volume %s to webhook", metadata.EventType, metadata.PVName)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("failed to post %s event of volume %s to webhook status code: %d error: %s",
+			metadata.EventType, metadata.PVName, resp.StatusCode, string(body))
+	}
+	klog.V(4).Infof("Posted %s event of volume %s to webhook", metadata.EventType, metadata.PVName)
+	return nil
+}
+
+// renderPayload returns the serialized webhook payload for given event
+func (n *Notifier) renderPayload(metadata collectorinterface.EventMetadata, data string) ([]byte, error) {
+	messageData, err := newMessageData(metadata, data)
+	if err != nil {
+		return nil, err
+	}
+	var message bytes.Buffer
+	if err := n.template.Execute(&message, messageData); err != nil {
+		return nil, errors.Wrapf(err, "failed to render message of %s event of volume %s", metadata.EventType, metadata.PVName)
+	}
+	summary := "Volume " + metadata.PVName + " " + action(metadata.EventType)
+	payload, err := json.Marshal(n.platform.newPayload(message.String(), summary))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal webhook payload")
+	}
+	return payload, nil
+}
@@ -0,0 +1,260 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chatops
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeCollector implements VolumeEventCollector, only
+// GetDataType is used by the chat-ops client
+type fakeCollector struct {
+	collectorinterface.VolumeEventCollector
+}
+
+func (f *fakeCollector) GetDataType() collectorinterface.DataType {
+	return collectorinterface.JSONDataType
+}
+
+func newFakeNFSVolumeData(isDeleted, isPVCExist bool) *nfspv.NFSVolumeData {
+	createdAt := metav1.NewTime(time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC))
+	volumeData := &nfspv.NFSVolumeData{
+		NFSPV: &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pvc-1",
+				UID:               "uid-1",
+				CreationTimestamp: createdAt,
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName: "openebs-rwx",
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("5Gi"),
+				},
+				ClaimRef: &corev1.ObjectReference{Namespace: "app", Name: "data"},
+			},
+		},
+		BackingPVC: &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openebs", Name: "nfs-pvc-1"},
+		},
+		BackingPV: &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-2"},
+		},
+	}
+	if isPVCExist {
+		volumeData.NFSPVC = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "data"},
+		}
+	}
+	if isDeleted {
+		deletedAt := metav1.NewTime(createdAt.Add(50*time.Hour + 30*time.Minute))
+		volumeData.NFSPV.DeletionTimestamp = &deletedAt
+	}
+	return volumeData
+}
+
+func marshal(t *testing.T, obj interface{}) string {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal %v: %v", obj, err)
+	}
+	return string(data)
+}
+
+func TestRenderPayload(t *testing.T) {
+	tests := map[string]struct {
+		platform        string
+		template        string
+		eventType       collectorinterface.EventType
+		data            interface{}
+		expectedPayload interface{}
+		isErrExpected   bool
+	}{
+		"when create event is rendered for slack": {
+			platform:  slackPlatform,
+			template:  defaultTemplate,
+			eventType: collectorinterface.CreateEventType,
+			data:      nfspv.NFSCreateVolumeData{VolumeProvisioned: newFakeNFSVolumeData(false, true)},
+			expectedPayload: textPayload{
+				Text: "*Volume pvc-1 provisioned*\n" +
+					"- PVC: app/data\n" +
+					"- Capacity: 5Gi\n" +
+					"- StorageClass: openebs-rwx\n" +
+					"- Backing volume: pvc-2 (openebs/nfs-pvc-1)",
+			},
+		},
+		"when delete event is rendered for teams": {
+			platform:  teamsPlatform,
+			template:  defaultTemplate,
+			eventType: collectorinterface.DeleteEventType,
+			data:      nfspv.NFSDeleteVolumeData{VolumeDeleted: newFakeNFSVolumeData(true, true)},
+			expectedPayload: messageCardPayload{
+				Type:    "MessageCard",
+				Context: "https://schema.org/extensions",
+				Summary: "Volume pvc-1 deleted",
+				Text: "**Volume pvc-1 deleted**\n" +
+					"- PVC: app/data\n" +
+					"- Capacity: 5Gi\n" +
+					"- StorageClass: openebs-rwx\n" +
+					"- Backing volume: pvc-2 (openebs/nfs-pvc-1)\n" +
+					"- Lifetime: 2d 2h30m0s",
+			},
+		},
+		"when delete event of volume without NFS PVC is rendered for mattermost": {
+			platform:  mattermostPlatform,
+			template:  "{{ bold .PVName }} of {{ .PVCNamespace }}/{{ .PVCName }} lived {{ duration .Lifetime }}",
+			eventType: collectorinterface.DeleteEventType,
+			data:      nfspv.NFSDeleteVolumeData{VolumeDeleted: newFakeNFSVolumeData(true, false)},
+			expectedPayload: textPayload{
+				Text: "**pvc-1** of app/data lived 2d 2h30m0s",
+			},
+		},
+		"when volume data is missing": {
+			platform:      slackPlatform,
+			template:      defaultTemplate,
+			eventType:     collectorinterface.CreateEventType,
+			data:          nfspv.NFSCreateVolumeData{},
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			notifier, err := newNotifier("http://localhost", test.platform, test.template, time.Second)
+			if err != nil {
+				t.Fatalf("%q test failed to create notifier: %v", name, err)
+			}
+			payload, err := notifier.renderPayload(collectorinterface.EventMetadata{
+				EventType: test.eventType,
+				PVName:    "pvc-1",
+				PVUID:     "uid-1",
+				CASType:   nfspv.OpenEBSNFSCASLabelValue,
+			}, marshal(t, test.data))
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if !test.isErrExpected {
+				if diff := cmp.Diff(marshal(t, test.expectedPayload), string(payload)); diff != "" {
+					t.Fatalf("%q test failed payload mismatch (-want +got):\n%s", name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	tests := map[string]struct {
+		platform      string
+		template      string
+		isErrExpected bool
+	}{
+		"when default template is used": {
+			platform: slackPlatform,
+			template: defaultTemplate,
+		},
+		"when platform is not supported": {
+			platform:      "irc",
+			template:      defaultTemplate,
+			isErrExpected: true,
+		},
+		"when template is malformed": {
+			platform:      teamsPlatform,
+			template:      "{{ .PVName ",
+			isErrExpected: true,
+		},
+		"when template refers unknown field": {
+			platform:      teamsPlatform,
+			template:      "{{ .Unknown }}",
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := newNotifier("http://localhost", test.platform, test.template, time.Second)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	tests := map[string]struct {
+		statusCode    int
+		isErrExpected bool
+	}{
+		"when webhook accepts the message": {
+			statusCode: http.StatusOK,
+		},
+		"when webhook rejects the message": {
+			statusCode:    http.StatusBadRequest,
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var received textPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &received)
+				w.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			notifier, err := newNotifier(server.URL, slackPlatform, "{{ .PVName }} {{ action .EventType }}", time.Second)
+			if err != nil {
+				t.Fatalf("%q test failed to create notifier: %v", name, err)
+			}
+			err = notifier.NewChatOpsClient(&fakeCollector{}).Send(collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pvc-1",
+				CASType:   nfspv.OpenEBSNFSCASLabelValue,
+			}, marshal(t, nfspv.NFSCreateVolumeData{VolumeProvisioned: newFakeNFSVolumeData(false, true)}))
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if received.Text != "pvc-1 provisioned" {
+				t.Fatalf("%q test failed expected message %q but got %q", name, "pvc-1 provisioned", received.Text)
+			}
+		})
+	}
+}
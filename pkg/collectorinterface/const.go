@@ -23,12 +23,25 @@ const (
 	// VolumeDeleteEventAnnotation holds annotation key which represents
 	// status of volume deletion event
 	VolumeDeleteEventAnnotation = "event.openebs.io/volume-delete"
+	// StorageClassSnapshotAnnotation holds annotation key which holds the
+	// StorageClass snapshots captured while sending create event so that
+	// delete event carries them even after StorageClass is deleted
+	StorageClassSnapshotAnnotation = "event.openebs.io/storage-class-snapshot"
 	// OpenebsEventSentAnnotationValue holds annotation value which states
 	// corresponding volume event was sent to server
 	OpenebsEventSentAnnotationValue = "sent"
 	// VolumeEventsFinalizer holds finalizer value to ensure delivery of volume
 	// events
 	VolumeEventsFinalizer = "events.openebs.io/finalizer"
+	// VolumeEventsRequiredAnnotation holds annotation key which states
+	// volume events has to be exported
+	VolumeEventsRequiredAnnotation = "events.openebs.io/required"
+	// VolumeEventOriginAnnotation holds annotation key which states how
+	// create event of the volume is generated
+	VolumeEventOriginAnnotation = "events.openebs.io/origin"
+	// BackfilledEventOrigin states that create event is generated for
+	// volume provisioned before volume events are enabled
+	BackfilledEventOrigin = "synthetic/backfilled"
 )
 
 type DataType string
@@ -37,3 +50,12 @@ const (
 	JSONDataType DataType = "JSON"
 	YAMLDataType DataType = "YAML"
 )
+
+type EventType string
+
+const (
+	// CreateEventType represents volume creation event
+	CreateEventType EventType = "create"
+	// DeleteEventType represents volume deletion event
+	DeleteEventType EventType = "delete"
+)
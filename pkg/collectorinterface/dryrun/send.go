@@ -0,0 +1,132 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Sink logs the rendered volume events or writes them to a file
+// instead of sending them to the configured destination
+type Sink struct {
+	// mutex serializes writes to the file
+	mutex sync.Mutex
+
+	// file is nil if events are only logged
+	file *os.File
+
+	// now is used to get current time, helpful in tests
+	now func() time.Time
+}
+
+// DryRunClient renders volume events collected by the collector to the sink
+type DryRunClient struct {
+	*Sink
+	// VolumeCollector implements methods required for event collector
+	collectorinterface.VolumeEventCollector
+}
+
+// Record is a single line written to the file
+type Record struct {
+	EventType collectorinterface.EventType `json:"event_type"`
+	PVName    string                       `json:"pv_name"`
+	PVUID     string                       `json:"pv_uid"`
+	CASType   string                       `json:"cas_type"`
+	Origin    string                       `json:"origin,omitempty"`
+	// Timestamp at which event is rendered
+	Timestamp time.Time `json:"timestamp"`
+	// Payload is the data which would be sent to the destination
+	Payload string `json:"payload"`
+}
+
+// NewSink returns the sink which appends rendered events to the file
+// at given path as JSON Lines. Events are only logged if path is empty
+func NewSink(path string) (*Sink, error) {
+	s := &Sink{
+		now: time.Now,
+	}
+	if path == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory of %s", path)
+	}
+	file, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open dry run output file %s", path)
+	}
+	s.file = file
+	return s, nil
+}
+
+// NewDryRunClient returns events sender which renders events
+// collected by given collector to the sink
+func (s *Sink) NewDryRunClient(collectorInterface collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	return &DryRunClient{
+		Sink:                 s,
+		VolumeEventCollector: collectorInterface,
+	}
+}
+
+// Send logs the event or appends it as a single line to the file
+func (d *DryRunClient) Send(metadata collectorinterface.EventMetadata, data string) error {
+	if d.file == nil {
+		klog.Infof("Dry run %s event of volume %s: %s", metadata.EventType, metadata.PVName, data)
+		return nil
+	}
+
+	line, err := json.Marshal(&Record{
+		EventType: metadata.EventType,
+		PVName:    metadata.PVName,
+		PVUID:     metadata.PVUID,
+		CASType:   metadata.CASType,
+		Origin:    metadata.Origin,
+		Timestamp: d.now().UTC(),
+		Payload:   data,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s event of volume %s", metadata.EventType, metadata.PVName)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if _, err := d.file.Write(append(line, '\n')); err != nil {
+		return errors.Wrapf(err, "failed to write %s event of volume %s", metadata.EventType, metadata.PVName)
+	}
+	klog.Infof("Written dry run %s event of volume %s to file %s", metadata.EventType, metadata.PVName, d.file.Name())
+	return nil
+}
+
+// Close closes the file of the sink
+func (s *Sink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+)
+
+func TestSend(t *testing.T) {
+	now := time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		path            string
+		events          []collectorinterface.EventMetadata
+		expectedRecords []Record
+	}{
+		"when events are written to file": {
+			path: filepath.Join(t.TempDir(), "events", "dry-run.jsonl"),
+			events: []collectorinterface.EventMetadata{
+				{EventType: collectorinterface.CreateEventType, PVName: "pv1", PVUID: "uid1", CASType: "nfs-kernel"},
+				{EventType: collectorinterface.DeleteEventType, PVName: "pv1", PVUID: "uid1", CASType: "nfs-kernel"},
+			},
+			expectedRecords: []Record{
+				{EventType: collectorinterface.CreateEventType, PVName: "pv1", PVUID: "uid1", CASType: "nfs-kernel", Timestamp: now, Payload: "{}"},
+				{EventType: collectorinterface.DeleteEventType, PVName: "pv1", PVUID: "uid1", CASType: "nfs-kernel", Timestamp: now, Payload: "{}"},
+			},
+		},
+		"when events are only logged": {
+			events: []collectorinterface.EventMetadata{
+				{EventType: collectorinterface.CreateEventType, PVName: "pv1", PVUID: "uid1", CASType: "nfs-kernel"},
+			},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			sink, err := NewSink(test.path)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			sink.now = func() time.Time { return now }
+			client := sink.NewDryRunClient(nil)
+			for _, metadata := range test.events {
+				if err := client.Send(metadata, "{}"); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+			if err := sink.Close(); err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if test.path == "" {
+				return
+			}
+
+			content, err := ioutil.ReadFile(test.path)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+			if len(lines) != len(test.expectedRecords) {
+				t.Fatalf("%q test failed expected %d records but got %d", name, len(test.expectedRecords), len(lines))
+			}
+			for i, line := range lines {
+				var record Record
+				if err := json.Unmarshal([]byte(line), &record); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+				if record != test.expectedRecords[i] {
+					t.Errorf("%q test failed expected record %+v but got %+v", name, test.expectedRecords[i], record)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exechook
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Environment variables passed to the executable
+	eventTypeEnv = "VOLUME_EVENT_TYPE"
+	pvNameEnv    = "VOLUME_PV_NAME"
+	pvUIDEnv     = "VOLUME_PV_UID"
+	casTypeEnv   = "VOLUME_CAS_TYPE"
+	dataTypeEnv  = "VOLUME_EVENT_DATA_TYPE"
+
+	// maxStderrLength limits the stderr output included in errors
+	maxStderrLength = 1024
+
+	// killGracePeriod is the time to wait for the killed executable
+	// to release stdin and stderr pipes
+	killGracePeriod = 5 * time.Second
+)
+
+// Hook holds the executable configuration
+type Hook struct {
+	// command is the path of the executable
+	command string
+	// args are passed to the executable
+	args []string
+	// timeout after which executable is killed
+	timeout time.Duration
+	// env is the environment passed to the executable in addition
+	// to the event details. It holds only PATH and allowed variables
+	// so that credentials of other sinks are not exposed
+	env []string
+}
+
+// ExecClient pipes volume events to the configured executable
+type ExecClient struct {
+	*Hook
+	// VolumeCollector implements methods required for event collector
+	collectorinterface.VolumeEventCollector
+}
+
+// NewHook returns the hook configured via environment variables
+func NewHook() (*Hook, error) {
+	command := env.GetExecSinkCommand()
+	if command == "" {
+		return nil, errors.Errorf("executable is not configured, set %s", env.ExecSinkCommand)
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find executable %s", command)
+	}
+	return newHook(path, env.GetExecSinkArgs(), env.GetExecSinkTimeout(), env.GetExecSinkEnvAllowList()), nil
+}
+
+func newHook(command string, args []string, timeout time.Duration, envAllowList []string) *Hook {
+	return &Hook{
+		command: command,
+		args:    args,
+		timeout: timeout,
+		env:     getHookEnv(envAllowList),
+	}
+}
+
+// getHookEnv returns PATH and the allowed variables which
+// are set in the environment of the exporter
+func getHookEnv(envAllowList []string) []string {
+	var hookEnv []string
+	for _, name := range append([]string{"PATH"}, envAllowList...) {
+		if value, isExist := os.LookupEnv(name); isExist {
+			hookEnv = append(hookEnv, name+"="+value)
+		}
+	}
+	return hookEnv
+}
+
+// NewExecClient returns events sender which pipes events
+// collected by given collector to the executable
+func (h *Hook) NewExecClient(collectorInterface collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	return &ExecClient{
+		Hook:                 h,
+		VolumeEventCollector: collectorInterface,
+	}
+}
+
+// Send runs the executable with data on stdin. Event details are
+// passed as environment variables. Exit code 0 is treated as success
+// and anything else is returned as error so that event will be retried.
+// On timeout the whole process group of the executable is killed so that
+// processes spawned by it can't keep Send blocked
+func (e *ExecClient) Send(metadata collectorinterface.EventMetadata, data string) error {
+	var stderr bytes.Buffer
+	// #nosec G204 -- executable is configured by the administrator
+	cmd := exec.Command(e.command, e.args...)
+	cmd.Stdin = strings.NewReader(data)
+	cmd.Stderr = &stderr
+	cmd.Env = append(append([]string{}, e.env...),
+		eventTypeEnv+"="+string(metadata.EventType),
+		pvNameEnv+"="+metadata.PVName,
+		pvUIDEnv+"="+metadata.PVUID,
+		casTypeEnv+"="+metadata.CASType,
+		dataTypeEnv+"="+string(e.GetDataType()),
+	)
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return errors.Wrapf(err, "failed to start executable %s for %s event of volume %s", e.command, metadata.EventType, metadata.PVName)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	timer := time.NewTimer(e.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			return errors.Wrapf(err, "executable %s failed for %s event of volume %s stderr: %s",
+				e.command, metadata.EventType, metadata.PVName, truncate(stderr.String(), maxStderrLength))
+		}
+	case <-timer.C:
+		if err := killProcessGroup(cmd); err != nil {
+			klog.Errorf("Failed to kill executable %s: %v", e.command, err)
+		}
+		// Processes which left the process group can still hold the
+		// pipes, don't wait for them beyond grace period
+		select {
+		case <-done:
+		case <-time.After(killGracePeriod):
+			klog.Warningf("Executable %s didn't release pipes within %s after it is killed", e.command, killGracePeriod)
+		}
+		return errors.Errorf("executable %s timed out after %s for %s event of volume %s", e.command, e.timeout, metadata.EventType, metadata.PVName)
+	}
+	klog.V(4).Infof("Executable %s processed %s event of volume %s", e.command, metadata.EventType, metadata.PVName)
+	return nil
+}
+
+func truncate(s string, length int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= length {
+		return s
+	}
+	return s[:length] + "..."
+}
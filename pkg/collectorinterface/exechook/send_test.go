@@ -0,0 +1,128 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exechook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+)
+
+// fakeCollector implements VolumeEventCollector, only
+// GetDataType is used by the exec client
+type fakeCollector struct {
+	collectorinterface.VolumeEventCollector
+}
+
+func (f *fakeCollector) GetDataType() collectorinterface.DataType {
+	return collectorinterface.JSONDataType
+}
+
+func TestSend(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "output")
+	tests := map[string]struct {
+		script         string
+		timeout        time.Duration
+		expectedOutput string
+		isErrExpected  bool
+	}{
+		"when executable exits with zero": {
+			script:         `echo "$VOLUME_EVENT_TYPE $VOLUME_PV_NAME $(cat)" > ` + outputFile,
+			timeout:        10 * time.Second,
+			expectedOutput: "delete pv1 {\"volume_deleted\":{}}\n",
+		},
+		"when executable exits with non-zero": {
+			script:        `echo "server unreachable" >&2; exit 3`,
+			timeout:       10 * time.Second,
+			isErrExpected: true,
+		},
+		"when executable runs beyond timeout": {
+			script:        `sleep 5`,
+			timeout:       100 * time.Millisecond,
+			isErrExpected: true,
+		},
+		"when process spawned by executable runs beyond timeout": {
+			script:        `sleep 5 & wait`,
+			timeout:       100 * time.Millisecond,
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			client := newHook("/bin/sh", []string{"-c", test.script}, test.timeout, nil).NewExecClient(&fakeCollector{})
+			start := time.Now()
+			err := client.Send(collectorinterface.EventMetadata{
+				EventType: collectorinterface.DeleteEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+			}, `{"volume_deleted":{}}`)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			// Send must return soon after timeout even if
+			// executable or its children are still running
+			if elapsed := time.Since(start); elapsed > test.timeout+2*time.Second {
+				t.Fatalf("%q test failed expected send to return within timeout %s but took %s", name, test.timeout, elapsed)
+			}
+			if test.expectedOutput != "" {
+				output, err := ioutil.ReadFile(outputFile)
+				if err != nil {
+					t.Fatalf("%q test failed to read output of executable: %v", name, err)
+				}
+				if string(output) != test.expectedOutput {
+					t.Fatalf("%q test failed expected output %q but got %q", name, test.expectedOutput, string(output))
+				}
+			}
+		})
+	}
+}
+
+func TestSendEnvironment(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "output")
+	for name, value := range map[string]string{"CALLBACK_TOKEN": "secret", "HOOK_REGION": "us-east-1"} {
+		os.Setenv(name, value)
+		defer os.Unsetenv(name)
+	}
+
+	script := `echo "token=$CALLBACK_TOKEN region=$HOOK_REGION pv=$VOLUME_PV_NAME" > ` + outputFile
+	client := newHook("/bin/sh", []string{"-c", script}, 10*time.Second, []string{"HOOK_REGION"}).NewExecClient(&fakeCollector{})
+	err := client.Send(collectorinterface.EventMetadata{
+		EventType: collectorinterface.CreateEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+	}, `{"volume_provisioned":{}}`)
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	output, err := ioutil.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output of executable: %v", err)
+	}
+	if expectedOutput := "token= region=us-east-1 pv=pv1\n"; string(output) != expectedOutput {
+		t.Fatalf("expected output %q but got %q", expectedOutput, string(output))
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	volumeeventsv1 "github.com/mayadata-io/volume-events-exporter/pkg/proto/volumeevents/v1"
+	"github.com/pkg/errors"
+)
+
+var eventTypes = map[collectorinterface.EventType]volumeeventsv1.EventType{
+	collectorinterface.CreateEventType: volumeeventsv1.EventType_EVENT_TYPE_CREATE,
+	collectorinterface.DeleteEventType: volumeeventsv1.EventType_EVENT_TYPE_DELETE,
+}
+
+// newVolumeEvent converts the serialized data collected by
+// collector of given CAS type into typed VolumeEvent
+func newVolumeEvent(metadata collectorinterface.EventMetadata, data string) (*volumeeventsv1.VolumeEvent, error) {
+	eventType, isExist := eventTypes[metadata.EventType]
+	if !isExist {
+		return nil, errors.Errorf("unsupported event type %q", metadata.EventType)
+	}
+	event := &volumeeventsv1.VolumeEvent{
+		EventType: eventType,
+		PvName:    metadata.PVName,
+		PvUid:     metadata.PVUID,
+		CasType:   metadata.CASType,
+		Origin:    metadata.Origin,
+		// Enrichment without typed fields is available only in JSON payload
+		JsonPayload: data,
+	}
+
+	switch metadata.CASType {
+	case nfspv.OpenEBSNFSCASLabelValue:
+		nfsVolume, tenant, err := newNFSVolume(metadata.EventType, data)
+		if err != nil {
+			return nil, err
+		}
+		event.Volume = &volumeeventsv1.VolumeEvent_NfsVolume{NfsVolume: nfsVolume}
+		event.Tenant = tenant
+	default:
+		return nil, errors.Errorf("conversion of %s volume data is not supported", metadata.CASType)
+	}
+	return event, nil
+}
+
+// newNFSVolume returns the typed NFS volume and tenant fields of the event
+func newNFSVolume(eventType collectorinterface.EventType, data string) (*volumeeventsv1.NFSVolume, map[string]string, error) {
+	volumeData, tenant, err := nfspv.UnmarshalNFSVolumeData(eventType, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &volumeeventsv1.NFSVolume{
+		NfsPvc:     volumeeventsv1.NewPersistentVolumeClaim(volumeData.NFSPVC),
+		NfsPv:      volumeeventsv1.NewPersistentVolume(volumeData.NFSPV),
+		BackingPvc: volumeeventsv1.NewPersistentVolumeClaim(volumeData.BackingPVC),
+		BackingPv:  volumeeventsv1.NewPersistentVolume(volumeData.BackingPV),
+	}, tenant, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/mayadata-io/volume-events-exporter/pkg/helper"
+	volumeeventsv1 "github.com/mayadata-io/volume-events-exporter/pkg/proto/volumeevents/v1"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpcmetadata "google.golang.org/grpc/metadata"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// TokenMetadataKey is the metadata key which holds the authentication token
+	TokenMetadataKey = "token"
+)
+
+// Connection holds the client connection to gRPC server. It is
+// shared across all the GRPCClient instances
+type Connection struct {
+	conn *grpc.ClientConn
+
+	client volumeeventsv1.VolumeEventServiceClient
+
+	// authToken is sent as metadata on every call
+	authToken string
+
+	// timeout is the deadline of each publish call
+	timeout time.Duration
+}
+
+// GRPCClient publishes volume events to gRPC server
+type GRPCClient struct {
+	*Connection
+	// VolumeCollector implements methods required for event collector
+	collectorinterface.VolumeEventCollector
+}
+
+// NewConnection dials the gRPC server configured via environment variables
+func NewConnection() (*Connection, error) {
+	address := env.GetGRPCServerAddress()
+	if address == "" {
+		return nil, errors.Errorf("gRPC server address is not configured, set %s", env.GRPCServerAddress)
+	}
+
+	transportCreds := grpc.WithInsecure()
+	if env.IsGRPCTLSEnabled() {
+		tlsConfig, err := helper.NewTLSConfig(
+			env.GetGRPCTLSCAFile(),
+			env.GetGRPCTLSCertFile(),
+			env.GetGRPCTLSKeyFile(),
+			false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to build gRPC TLS configuration")
+		}
+		transportCreds = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
+	// Dial is non-blocking, connection is established on first call
+	conn, err := grpc.Dial(address, transportCreds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial gRPC server %s", address)
+	}
+	return newConnection(conn, env.GetGRPCAuthToken(), env.GetGRPCTimeout()), nil
+}
+
+func newConnection(conn *grpc.ClientConn, authToken string, timeout time.Duration) *Connection {
+	return &Connection{
+		conn:      conn,
+		client:    volumeeventsv1.NewVolumeEventServiceClient(conn),
+		authToken: authToken,
+		timeout:   timeout,
+	}
+}
+
+// NewGRPCClient returns events sender which publishes events
+// collected by given collector
+func (c *Connection) NewGRPCClient(collectorInterface collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	return &GRPCClient{
+		Connection:           c,
+		VolumeEventCollector: collectorInterface,
+	}
+}
+
+// Close will close the connection with server
+func (c *Connection) Close() error {
+	return c.conn.Close()
+}
+
+// Send converts the data into typed VolumeEvent and publishes it
+// to the server. Deadline of the call is propagated to server
+func (g *GRPCClient) Send(metadata collectorinterface.EventMetadata, data string) error {
+	dataType := g.GetDataType()
+	if dataType != collectorinterface.JSONDataType {
+		return errors.Errorf("unsupported data type %s", dataType)
+	}
+
+	event, err := newVolumeEvent(metadata, data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	if g.authToken != "" {
+		ctx = grpcmetadata.AppendToOutgoingContext(ctx, TokenMetadataKey, g.authToken)
+	}
+
+	_, err = g.client.PublishVolumeEvent(ctx, &volumeeventsv1.PublishVolumeEventRequest{Event: event})
+	if err != nil {
+		return errors.Wrapf(err, "failed to publish %s event of volume %s", metadata.EventType, metadata.PVName)
+	}
+	klog.V(4).Infof("Published %s event of volume %s to gRPC server", metadata.EventType, metadata.PVName)
+	return nil
+}
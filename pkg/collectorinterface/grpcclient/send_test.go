@@ -0,0 +1,209 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	volumeeventsv1 "github.com/mayadata-io/volume-events-exporter/pkg/proto/volumeevents/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeCollector implements VolumeEventCollector, only
+// GetDataType is used by the gRPC client
+type fakeCollector struct {
+	collectorinterface.VolumeEventCollector
+}
+
+func (f *fakeCollector) GetDataType() collectorinterface.DataType {
+	return collectorinterface.JSONDataType
+}
+
+// fakeServer records the received events and fails
+// the calls which doesn't have expected token
+type fakeServer struct {
+	volumeeventsv1.UnimplementedVolumeEventServiceServer
+	token          string
+	receivedEvents []*volumeeventsv1.VolumeEvent
+}
+
+func (f *fakeServer) PublishVolumeEvent(ctx context.Context,
+	req *volumeeventsv1.PublishVolumeEventRequest) (*volumeeventsv1.PublishVolumeEventResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if tokens := md.Get(TokenMetadataKey); len(tokens) == 0 || tokens[0] != f.token {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if _, isDeadlineSet := ctx.Deadline(); !isDeadlineSet {
+		return nil, status.Error(codes.InvalidArgument, "deadline is not propagated")
+	}
+	f.receivedEvents = append(f.receivedEvents, req.GetEvent())
+	return &volumeeventsv1.PublishVolumeEventResponse{}, nil
+}
+
+func TestSend(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	fakeSrv := &fakeServer{token: "valid-token"}
+	volumeeventsv1.RegisterVolumeEventServiceServer(srv, fakeSrv)
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial fake server: %v", err)
+	}
+	defer conn.Close()
+
+	nfsVolumeData := &nfspv.NFSVolumeData{
+		NFSPV: &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pv1",
+				UID:               "uid-1",
+				CreationTimestamp: metav1.Now(),
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("5Gi"),
+				},
+			},
+		},
+		BackingPVC: &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nfs-pv1",
+				Namespace: "openebs",
+			},
+		},
+		BackingPV: &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "backend-pv1",
+			},
+		},
+	}
+	createData, _ := json.Marshal(&nfspv.NFSCreateVolumeData{VolumeProvisioned: nfsVolumeData})
+	backfilledCreateData, _ := json.Marshal(&nfspv.NFSCreateVolumeData{
+		VolumeProvisioned: nfsVolumeData,
+		Tenant:            map[string]string{"team": "storage"},
+		Origin:            collectorinterface.BackfilledEventOrigin,
+	})
+
+	tests := map[string]struct {
+		metadata       collectorinterface.EventMetadata
+		data           string
+		token          string
+		expectedTenant map[string]string
+		isErrExpected  bool
+	}{
+		"when NFS create event is published": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+				CASType:   nfspv.OpenEBSNFSCASLabelValue,
+			},
+			data:  string(createData),
+			token: "valid-token",
+		},
+		"when backfilled NFS create event with tenant is published": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+				CASType:   nfspv.OpenEBSNFSCASLabelValue,
+				Origin:    collectorinterface.BackfilledEventOrigin,
+			},
+			data:           string(backfilledCreateData),
+			token:          "valid-token",
+			expectedTenant: map[string]string{"team": "storage"},
+		},
+		"when token is invalid": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+				CASType:   nfspv.OpenEBSNFSCASLabelValue,
+			},
+			data:          string(createData),
+			token:         "invalid-token",
+			isErrExpected: true,
+		},
+		"when CAS type is not supported": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv2",
+				PVUID:     "uid-2",
+				CASType:   "local",
+			},
+			data:          "{}",
+			token:         "valid-token",
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			fakeSrv.receivedEvents = nil
+			client := newConnection(conn, test.token, 5*time.Second).NewGRPCClient(&fakeCollector{})
+			err := client.Send(test.metadata, test.data)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if !test.isErrExpected {
+				if len(fakeSrv.receivedEvents) != 1 {
+					t.Fatalf("%q test failed expected server to receive 1 event but got %d", name, len(fakeSrv.receivedEvents))
+				}
+				event := fakeSrv.receivedEvents[0]
+				if event.GetPvUid() != test.metadata.PVUID {
+					t.Fatalf("%q test failed expected PV UID %s but got %s", name, test.metadata.PVUID, event.GetPvUid())
+				}
+				if event.GetNfsVolume().GetNfsPv().GetCapacity() != "5Gi" {
+					t.Fatalf("%q test failed expected NFS PV capacity 5Gi but got %s", name, event.GetNfsVolume().GetNfsPv().GetCapacity())
+				}
+				if event.GetOrigin() != test.metadata.Origin {
+					t.Fatalf("%q test failed expected origin %q but got %q", name, test.metadata.Origin, event.GetOrigin())
+				}
+				if !reflect.DeepEqual(event.GetTenant(), test.expectedTenant) {
+					t.Fatalf("%q test failed expected tenant %v but got %v", name, test.expectedTenant, event.GetTenant())
+				}
+				if event.GetJsonPayload() != test.data {
+					t.Fatalf("%q test failed expected JSON payload to be carried in event", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonlines
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// FileClient appends volume events to a file as JSON Lines
+type FileClient struct {
+	*Writer
+	// VolumeCollector implements methods required for event collector
+	collectorinterface.VolumeEventCollector
+}
+
+// Record is a single line written to the file
+type Record struct {
+	EventType collectorinterface.EventType `json:"event_type"`
+	PVName    string                       `json:"pv_name"`
+	PVUID     string                       `json:"pv_uid"`
+	CASType   string                       `json:"cas_type"`
+	// Origin states how the event is generated ex: backfill, it
+	// is omitted for events generated from the lifecycle of volume
+	Origin string `json:"origin,omitempty"`
+	// Timestamp at which record is written
+	Timestamp time.Time `json:"timestamp"`
+	// Data holds the event data collected by the collector
+	Data json.RawMessage `json:"data"`
+}
+
+// NewFileWriter opens the file configured via environment variables
+func NewFileWriter() (*Writer, error) {
+	return NewWriter(
+		env.GetFileSinkPath(),
+		env.GetFileSinkMaxSize(),
+		env.GetFileSinkRotationInterval(),
+		env.IsFileSinkCompressEnabled())
+}
+
+// NewFileClient returns events sender which appends events
+// collected by given collector to the file
+func (w *Writer) NewFileClient(collectorInterface collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	return &FileClient{
+		Writer:               w,
+		VolumeEventCollector: collectorInterface,
+	}
+}
+
+// Send appends the event as a single line to the file. Send returns
+// only after the line is synced to the stable storage
+func (f *FileClient) Send(metadata collectorinterface.EventMetadata, data string) error {
+	dataType := f.GetDataType()
+	if dataType != collectorinterface.JSONDataType {
+		return errors.Errorf("unsupported data type %s", dataType)
+	}
+
+	// Data must fit in a single line
+	var compactData bytes.Buffer
+	if err := json.Compact(&compactData, []byte(data)); err != nil {
+		return errors.Wrapf(err, "invalid %s event data of volume %s", metadata.EventType, metadata.PVName)
+	}
+
+	line, err := json.Marshal(&Record{
+		EventType: metadata.EventType,
+		PVName:    metadata.PVName,
+		PVUID:     metadata.PVUID,
+		CASType:   metadata.CASType,
+		Origin:    metadata.Origin,
+		Timestamp: f.now().UTC(),
+		Data:      compactData.Bytes(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s event of volume %s", metadata.EventType, metadata.PVName)
+	}
+
+	if err := f.WriteLine(line); err != nil {
+		return errors.Wrapf(err, "failed to write %s event of volume %s", metadata.EventType, metadata.PVName)
+	}
+	klog.V(4).Infof("Written %s event of volume %s to file %s", metadata.EventType, metadata.PVName, f.path)
+	return nil
+}
@@ -0,0 +1,274 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonlines
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// rotatedFileTimeFormat is used to suffix rotated files
+	rotatedFileTimeFormat = "20060102T150405.000000000Z"
+
+	compressSuffix = ".gz"
+)
+
+// Writer appends lines to a file and rotates the file
+// based on size and time
+type Writer struct {
+	// mutex serializes writes and rotation
+	mutex sync.Mutex
+
+	// path of the active file
+	path string
+
+	// maxSize in bytes after which file is rotated
+	maxSize int64
+
+	// rotationInterval after which file is rotated, 0 disables
+	// time based rotation
+	rotationInterval time.Duration
+
+	// compress rotated files with gzip
+	compress bool
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// now is used to get current time, helpful in tests
+	now func() time.Time
+}
+
+// NewWriter opens(or creates) the file at given path in append mode
+func NewWriter(path string, maxSize int64, rotationInterval time.Duration, compress bool) (*Writer, error) {
+	w := &Writer{
+		path:             path,
+		maxSize:          maxSize,
+		rotationInterval: rotationInterval,
+		compress:         compress,
+		now:              time.Now,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory of %s", path)
+	}
+	if err := w.openFile(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// WriteLine appends the line to the file and flushes it to the
+// stable storage. Newline is appended to the given line. Rotated
+// file is compressed after releasing the lock so that concurrent
+// writes are not blocked on compression
+func (w *Writer) WriteLine(line []byte) error {
+	rotatedPath, err := w.writeLine(line)
+	if rotatedPath != "" && w.compress {
+		if err := compressFile(rotatedPath); err != nil {
+			// Data is still available in rotated file so
+			// just log the error
+			klog.Errorf("Failed to compress rotated file %s: %v", rotatedPath, err)
+		}
+	}
+	return err
+}
+
+// writeLine writes the line and returns the path of rotated file
+// if file is rotated before writing the line
+func (w *Writer) writeLine(line []byte) (string, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return "", errors.Errorf("file %s is closed", w.path)
+	}
+
+	var rotatedPath string
+	lineSize := int64(len(line) + 1)
+	if w.shouldRotate(lineSize) {
+		var err error
+		rotatedPath, err = w.rotate()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err := w.file.Write(append(line, '\n'))
+	if err != nil {
+		return rotatedPath, w.discardLine(errors.Wrapf(err, "failed to write to file %s", w.path))
+	}
+	if err := w.file.Sync(); err != nil {
+		return rotatedPath, w.discardLine(errors.Wrapf(err, "failed to sync file %s", w.path))
+	}
+	w.size += lineSize
+	return rotatedPath, nil
+}
+
+// discardLine truncates the partially written line so that the retry
+// of line doesn't corrupt the file and returns the given write error.
+// If truncation fails then the file is closed and later writes are
+// rejected
+func (w *Writer) discardLine(writeErr error) error {
+	if err := w.file.Truncate(w.size); err != nil {
+		klog.Errorf("Failed to truncate file %s to %d bytes after failed write, rejecting later writes: %v", w.path, w.size, err)
+		_ = w.file.Close()
+		w.file = nil
+	}
+	return writeErr
+}
+
+// Close closes the active file
+func (w *Writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// shouldRotate returns true if writing lineSize bytes exceeds the max
+// size or file is opened before rotation interval. Empty file is never rotated
+func (w *Writer) shouldRotate(lineSize int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.size+lineSize > w.maxSize {
+		return true
+	}
+	return w.rotationInterval > 0 && w.now().Sub(w.openedAt) >= w.rotationInterval
+}
+
+// rotate renames the active file with timestamp suffix and opens a
+// new file. It returns the path of rotated file. If rotation fails
+// then the active file is opened again so that only the rotation is
+// lost and later writes can still succeed
+func (w *Writer) rotate() (string, error) {
+	err := w.file.Close()
+	w.file = nil
+	if err != nil {
+		return "", w.reopen(errors.Wrapf(err, "failed to close file %s", w.path))
+	}
+
+	rotatedPath := w.rotatedPath()
+	if err := os.Rename(w.path, rotatedPath); err != nil {
+		return "", w.reopen(errors.Wrapf(err, "failed to rotate file %s", w.path))
+	}
+	// openFile syncs the directory hence rename is also persisted
+	if err := w.openFile(); err != nil {
+		// Move the rotated file back to continue appending to it
+		if renameErr := os.Rename(rotatedPath, w.path); renameErr != nil {
+			klog.Errorf("Failed to restore rotated file %s to %s: %v", rotatedPath, w.path, renameErr)
+		}
+		return "", w.reopen(err)
+	}
+	return rotatedPath, nil
+}
+
+// reopen opens the active file after failed rotation and returns the
+// given rotation error. Open time of the file is retained so that
+// time based rotation is not postponed
+func (w *Writer) reopen(rotateErr error) error {
+	openedAt := w.openedAt
+	if err := w.openFile(); err != nil {
+		klog.Errorf("Failed to reopen file %s after failed rotation: %v", w.path, err)
+		return rotateErr
+	}
+	w.openedAt = openedAt
+	return rotateErr
+}
+
+// rotatedPath returns path of the rotated file ex: events-20211018T101010.000000000Z.jsonl
+func (w *Writer) rotatedPath() string {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext)
+	return base + "-" + w.now().UTC().Format(rotatedFileTimeFormat) + ext
+}
+
+func (w *Writer) openFile() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open file %s", w.path)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return errors.Wrapf(err, "failed to stat file %s", w.path)
+	}
+	// File might be created or renamed, so directory is synced
+	if err := syncDir(filepath.Dir(w.path)); err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.now()
+	// Existing file is aged from its modification time so
+	// that restarts don't postpone time based rotation
+	if w.size != 0 && info.ModTime().Before(w.openedAt) {
+		w.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// compressFile compresses the given file into <path>.gz and
+// removes the original file once compressed data is synced
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	gzWriter := gzip.NewWriter(dst)
+	if _, err := io.Copy(gzWriter, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := gzWriter.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
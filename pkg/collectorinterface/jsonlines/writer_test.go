@@ -0,0 +1,215 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonlines
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteLine(t *testing.T) {
+	tests := map[string]struct {
+		maxSize          int64
+		rotationInterval time.Duration
+		compress         bool
+		// timeStep is added to the clock before every write
+		timeStep              time.Duration
+		lines                 []string
+		expectedActiveLines   int
+		expectedRotatedFiles  int
+		expectedRotatedSuffix string
+	}{
+		"when lines fit in a single file": {
+			maxSize:              1024,
+			lines:                []string{`{"a":1}`, `{"a":2}`},
+			expectedActiveLines:  2,
+			expectedRotatedFiles: 0,
+		},
+		"when file exceeds max size": {
+			maxSize:               10,
+			lines:                 []string{`{"a":1}`, `{"a":2}`, `{"a":3}`},
+			expectedActiveLines:   1,
+			expectedRotatedFiles:  2,
+			expectedRotatedSuffix: ".jsonl",
+		},
+		"when file is older than rotation interval": {
+			maxSize:               1024,
+			rotationInterval:      time.Hour,
+			timeStep:              time.Hour,
+			lines:                 []string{`{"a":1}`, `{"a":2}`},
+			expectedActiveLines:   1,
+			expectedRotatedFiles:  1,
+			expectedRotatedSuffix: ".jsonl",
+		},
+		"when rotated files are compressed": {
+			maxSize:               10,
+			compress:              true,
+			lines:                 []string{`{"a":1}`, `{"a":2}`},
+			expectedActiveLines:   1,
+			expectedRotatedFiles:  1,
+			expectedRotatedSuffix: ".jsonl.gz",
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "events.jsonl")
+			clock := time.Date(2021, 10, 18, 0, 0, 0, 0, time.UTC)
+
+			w, err := NewWriter(path, test.maxSize, test.rotationInterval, test.compress)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur while creating writer but got %v", name, err)
+			}
+			w.now = func() time.Time { return clock }
+			w.openedAt = clock
+			for _, line := range test.lines {
+				clock = clock.Add(test.timeStep + time.Second)
+				if err := w.WriteLine([]byte(line)); err != nil {
+					t.Fatalf("%q test failed expected error not to occur while writing but got %v", name, err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("%q test failed expected error not to occur while closing but got %v", name, err)
+			}
+
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%q test failed to read active file: %v", name, err)
+			}
+			if gotLines := strings.Count(string(content), "\n"); gotLines != test.expectedActiveLines {
+				t.Fatalf("%q test failed expected %d lines in active file but got %d", name, test.expectedActiveLines, gotLines)
+			}
+
+			rotatedFiles, err := filepath.Glob(filepath.Join(dir, "events-*"))
+			if err != nil {
+				t.Fatalf("%q test failed to list rotated files: %v", name, err)
+			}
+			if len(rotatedFiles) != test.expectedRotatedFiles {
+				t.Fatalf("%q test failed expected %d rotated files but got %v", name, test.expectedRotatedFiles, rotatedFiles)
+			}
+			for _, rotatedFile := range rotatedFiles {
+				if !strings.HasSuffix(rotatedFile, test.expectedRotatedSuffix) {
+					t.Fatalf("%q test failed expected rotated file %s to have suffix %s", name, rotatedFile, test.expectedRotatedSuffix)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteLineAfterFailedRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+	clock := time.Date(2021, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	w, err := NewWriter(path, 10, 0, false)
+	if err != nil {
+		t.Fatalf("expected error not to occur while creating writer but got %v", err)
+	}
+	defer w.Close()
+	w.now = func() time.Time { return clock }
+	if err := w.WriteLine([]byte(`{"a":1}`)); err != nil {
+		t.Fatalf("expected error not to occur while writing but got %v", err)
+	}
+
+	// Non empty directory at the rotated path fails the rename
+	blocker := filepath.Join(w.rotatedPath(), "blocker")
+	if err := os.MkdirAll(blocker, 0750); err != nil {
+		t.Fatalf("failed to create directory %s: %v", blocker, err)
+	}
+	if err := w.WriteLine([]byte(`{"a":2}`)); err == nil {
+		t.Fatalf("expected error to occur while rotating but got nil")
+	}
+
+	// Writer must recover once rotation succeeds
+	clock = clock.Add(time.Second)
+	if err := w.WriteLine([]byte(`{"a":3}`)); err != nil {
+		t.Fatalf("expected error not to occur after failed rotation but got %v", err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read active file: %v", err)
+	}
+	if string(content) != "{\"a\":3}\n" {
+		t.Fatalf("expected only last line in active file but got %q", string(content))
+	}
+}
+
+func TestNewWriterOfExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+	if err := ioutil.WriteFile(path, []byte("{\"a\":1}\n"), 0640); err != nil {
+		t.Fatalf("failed to write file %s: %v", path, err)
+	}
+	modTime := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to change times of file %s: %v", path, err)
+	}
+
+	w, err := NewWriter(path, 1024, time.Hour, false)
+	if err != nil {
+		t.Fatalf("expected error not to occur while creating writer but got %v", err)
+	}
+	defer w.Close()
+	if err := w.WriteLine([]byte(`{"a":2}`)); err != nil {
+		t.Fatalf("expected error not to occur while writing but got %v", err)
+	}
+	rotatedFiles, err := filepath.Glob(filepath.Join(dir, "events-*"))
+	if err != nil {
+		t.Fatalf("failed to list rotated files: %v", err)
+	}
+	if len(rotatedFiles) != 1 {
+		t.Fatalf("expected file older than rotation interval to be rotated but got %v", rotatedFiles)
+	}
+}
+
+func TestDiscardLine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+
+	w, err := NewWriter(path, 1024, 0, false)
+	if err != nil {
+		t.Fatalf("expected error not to occur while creating writer but got %v", err)
+	}
+	defer w.Close()
+	if err := w.WriteLine([]byte(`{"a":1}`)); err != nil {
+		t.Fatalf("expected error not to occur while writing but got %v", err)
+	}
+
+	// Partially written line of failed write is discarded
+	if _, err := w.file.Write([]byte(`{"a":`)); err != nil {
+		t.Fatalf("failed to write partial line: %v", err)
+	}
+	if err := w.discardLine(os.ErrClosed); err != os.ErrClosed {
+		t.Fatalf("expected write error to be returned but got %v", err)
+	}
+	if err := w.WriteLine([]byte(`{"a":2}`)); err != nil {
+		t.Fatalf("expected error not to occur while retrying but got %v", err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read active file: %v", err)
+	}
+	if string(content) != "{\"a\":1}\n{\"a\":2}\n" {
+		t.Fatalf("expected partial line to be discarded but got %q", string(content))
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"github.com/Shopify/sarama"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/mayadata-io/volume-events-exporter/pkg/helper"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	clientID = "volume-events-exporter"
+)
+
+// Producer holds the connection to Kafka brokers. It is shared
+// across all the KafkaClient instances
+type Producer struct {
+	// producer publishes messages synchronously and waits for
+	// acknowledgement from all in-sync replicas
+	producer sarama.SyncProducer
+
+	// createEventTopic holds topic name to publish create events
+	createEventTopic string
+
+	// deleteEventTopic holds topic name to publish delete events
+	deleteEventTopic string
+}
+
+// KafkaClient publishes volume events to Kafka topics
+type KafkaClient struct {
+	*Producer
+	// VolumeCollector implements methods required for event collector
+	collectorinterface.VolumeEventCollector
+}
+
+// NewProducer connects to the Kafka brokers configured via environment variables
+func NewProducer() (*Producer, error) {
+	brokers := env.GetKafkaBrokers()
+	if len(brokers) == 0 {
+		return nil, errors.Errorf("kafka brokers are not configured, set %s", env.KafkaBrokers)
+	}
+
+	config, err := getProducerConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create kafka producer for brokers %v", brokers)
+	}
+	return newProducer(producer, env.GetKafkaCreateEventTopic(), env.GetKafkaDeleteEventTopic()), nil
+}
+
+func newProducer(producer sarama.SyncProducer, createEventTopic, deleteEventTopic string) *Producer {
+	return &Producer{
+		producer:         producer,
+		createEventTopic: createEventTopic,
+		deleteEventTopic: deleteEventTopic,
+	}
+}
+
+// NewKafkaClient returns events sender which publishes events
+// collected by given collector
+func (p *Producer) NewKafkaClient(collectorInterface collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	return &KafkaClient{
+		Producer:             p,
+		VolumeEventCollector: collectorInterface,
+	}
+}
+
+// Close will close the connection with brokers
+func (p *Producer) Close() error {
+	return p.producer.Close()
+}
+
+// Send publishes the data to the topic of corresponding event type.
+// UID of PV is used as the message key so that all the events of
+// a volume lands on the same partition and ordering is preserved.
+// Send returns only after all in-sync replicas acknowledged the message
+func (k *KafkaClient) Send(metadata collectorinterface.EventMetadata, data string) error {
+	dataType := k.GetDataType()
+	if dataType != collectorinterface.JSONDataType {
+		return errors.Errorf("unsupported data type %s", dataType)
+	}
+
+	var topic string
+	switch metadata.EventType {
+	case collectorinterface.CreateEventType:
+		topic = k.createEventTopic
+	case collectorinterface.DeleteEventType:
+		topic = k.deleteEventTopic
+	default:
+		return errors.Errorf("unsupported event type %q", metadata.EventType)
+	}
+
+	partition, offset, err := k.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(metadata.PVUID),
+		Value: sarama.StringEncoder(data),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to publish %s event of volume %s to topic %s", metadata.EventType, metadata.PVName, topic)
+	}
+	klog.V(4).Infof("Published %s event of volume %s to topic %s partition %d offset %d", metadata.EventType, metadata.PVName, topic, partition, offset)
+	return nil
+}
+
+func getProducerConfig() (*sarama.Config, error) {
+	config := sarama.NewConfig()
+	config.ClientID = clientID
+	// Events are considered delivered only after all
+	// in-sync replicas acknowledged the message
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Idempotent = true
+	config.Producer.Retry.Max = 5
+	// Idempotent producer requires only one in-flight request
+	config.Net.MaxOpenRequests = 1
+	config.Version = sarama.V2_1_0_0
+
+	if env.IsKafkaTLSEnabled() {
+		tlsConfig, err := helper.NewTLSConfig(
+			env.GetKafkaTLSCAFile(),
+			env.GetKafkaTLSCertFile(),
+			env.GetKafkaTLSKeyFile(),
+			env.IsKafkaTLSInsecureSkipVerify())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to build kafka TLS configuration")
+		}
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = tlsConfig
+	}
+
+	mechanism := env.GetKafkaSASLMechanism()
+	if mechanism != "" {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = env.GetKafkaSASLUser()
+		config.Net.SASL.Password = env.GetKafkaSASLPassword()
+		switch sarama.SASLMechanism(mechanism) {
+		case sarama.SASLTypePlaintext:
+			config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		case sarama.SASLTypeSCRAMSHA256:
+			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+			config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha256HashGenerator} }
+		case sarama.SASLTypeSCRAMSHA512:
+			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+			config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha512HashGenerator} }
+		default:
+			return nil, errors.Errorf("unsupported SASL mechanism %q", mechanism)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid kafka producer configuration")
+	}
+	return config, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+)
+
+// fakeCollector implements VolumeEventCollector, only
+// GetDataType is used by the Kafka client
+type fakeCollector struct {
+	collectorinterface.VolumeEventCollector
+	dataType collectorinterface.DataType
+}
+
+func (f *fakeCollector) GetDataType() collectorinterface.DataType {
+	return f.dataType
+}
+
+func TestSend(t *testing.T) {
+	tests := map[string]struct {
+		metadata      collectorinterface.EventMetadata
+		dataType      collectorinterface.DataType
+		produceErr    error
+		expectedTopic string
+		isErrExpected bool
+	}{
+		"when create event is published": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+			},
+			dataType:      collectorinterface.JSONDataType,
+			expectedTopic: "create-topic",
+		},
+		"when delete event is published": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.DeleteEventType,
+				PVName:    "pv2",
+				PVUID:     "uid-2",
+			},
+			dataType:      collectorinterface.JSONDataType,
+			expectedTopic: "delete-topic",
+		},
+		"when broker doesn't acknowledge the event": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.DeleteEventType,
+				PVName:    "pv3",
+				PVUID:     "uid-3",
+			},
+			dataType:      collectorinterface.JSONDataType,
+			produceErr:    sarama.ErrNotEnoughReplicas,
+			isErrExpected: true,
+		},
+		"when data type is not supported": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv4",
+				PVUID:     "uid-4",
+			},
+			dataType:      collectorinterface.YAMLDataType,
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			mockProducer := mocks.NewSyncProducer(t, nil)
+			defer mockProducer.Close()
+			switch {
+			case test.dataType != collectorinterface.JSONDataType:
+			case test.produceErr != nil:
+				mockProducer.ExpectSendMessageAndFail(test.produceErr)
+			default:
+				mockProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+					if msg.Topic != test.expectedTopic {
+						t.Errorf("%q test failed expected topic %s but got %s", name, test.expectedTopic, msg.Topic)
+					}
+					key, _ := msg.Key.Encode()
+					if string(key) != test.metadata.PVUID {
+						t.Errorf("%q test failed expected key %s but got %s", name, test.metadata.PVUID, string(key))
+					}
+					return nil
+				})
+			}
+			client := newProducer(mockProducer, "create-topic", "delete-topic").
+				NewKafkaClient(&fakeCollector{dataType: test.dataType})
+			err := client.Send(test.metadata, `{"volume_provisioned":{}}`)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nats
+
+import (
+	"bytes"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	clientName = "volume-events-exporter"
+)
+
+// eventActions maps event type to the action used in subjects
+var eventActions = map[collectorinterface.EventType]string{
+	collectorinterface.CreateEventType: "created",
+	collectorinterface.DeleteEventType: "deleted",
+}
+
+// Publisher holds the connection to NATS servers. It is shared
+// across all the NATSClient instances
+type Publisher struct {
+	conn *nats.Conn
+
+	// jetStream publishes messages and waits for acknowledgement
+	// from the stream
+	jetStream nats.JetStreamContext
+
+	// subjectTemplate builds the subject of an event
+	subjectTemplate *template.Template
+
+	// contentType is set as Content-Type header of messages, it
+	// depends on the payload rendered by configured template
+	contentType string
+
+	// closed is closed once the connection is closed
+	closed chan struct{}
+}
+
+// NATSClient publishes volume events to NATS JetStream
+type NATSClient struct {
+	*Publisher
+	// VolumeCollector implements methods required for event collector
+	collectorinterface.VolumeEventCollector
+}
+
+// subjectData is passed to the subject template
+type subjectData struct {
+	collectorinterface.EventMetadata
+	// Action is the past tense form of event type(created/deleted)
+	Action string
+}
+
+// NewPublisher connects to the NATS servers configured via environment variables
+func NewPublisher() (*Publisher, error) {
+	url := env.GetNATSURL()
+	if url == "" {
+		return nil, errors.Errorf("NATS URL is not configured, set %s", env.NATSURL)
+	}
+
+	opts := []nats.Option{nats.Name(clientName)}
+	if credsFile := env.GetNATSCredsFile(); credsFile != "" {
+		opts = append(opts, nats.UserCredentials(credsFile))
+	}
+	if caFile := env.GetNATSTLSCAFile(); caFile != "" {
+		opts = append(opts, nats.RootCAs(caFile))
+	}
+	if certFile := env.GetNATSTLSCertFile(); certFile != "" {
+		opts = append(opts, nats.ClientCert(certFile, env.GetNATSTLSKeyFile()))
+	}
+
+	conn, err := nats.Connect(url, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to NATS server %s", url)
+	}
+	publisher, err := newPublisher(conn, env.GetNATSSubjectTemplate(), env.GetNATSContentType())
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return publisher, nil
+}
+
+func newPublisher(conn *nats.Conn, subjectTemplate, contentType string) (*Publisher, error) {
+	tmpl, err := template.New("subject").Option("missingkey=error").Parse(subjectTemplate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse subject template %q", subjectTemplate)
+	}
+	jetStream, err := conn.JetStream()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get JetStream context")
+	}
+	closed := make(chan struct{})
+	var closeOnce sync.Once
+	conn.SetClosedHandler(func(_ *nats.Conn) {
+		closeOnce.Do(func() { close(closed) })
+	})
+	return &Publisher{
+		conn:            conn,
+		jetStream:       jetStream,
+		subjectTemplate: tmpl,
+		contentType:     contentType,
+		closed:          closed,
+	}, nil
+}
+
+// NewNATSClient returns events sender which publishes events
+// collected by given collector
+func (p *Publisher) NewNATSClient(collectorInterface collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	return &NATSClient{
+		Publisher:            p,
+		VolumeEventCollector: collectorInterface,
+	}
+}
+
+// Close will drain the pending messages and close the connection. It
+// returns only after the connection is closed so that in-flight
+// messages are not lost when the process exits
+func (p *Publisher) Close() error {
+	if p.conn.IsClosed() {
+		return nil
+	}
+	if err := p.conn.Drain(); err != nil {
+		p.conn.Close()
+		return errors.Wrapf(err, "failed to drain connection with NATS server")
+	}
+	// Connection is closed by the client once drain timeout elapses,
+	// additional second is given for the closed handler to run
+	timeout := p.conn.Opts.DrainTimeout + time.Second
+	select {
+	case <-p.closed:
+		return nil
+	case <-time.After(timeout):
+		p.conn.Close()
+		return errors.Errorf("timed out after %s draining connection with NATS server", timeout)
+	}
+}
+
+// Send publishes the data to the subject rendered from the subject
+// template. Send returns only after JetStream acknowledged the message.
+// Message ID is set from PV UID and event type so that JetStream drops
+// duplicates when an event is re-sent. Resend ID is appended for on
+// demand resends so that they are not dropped as duplicates
+func (n *NATSClient) Send(metadata collectorinterface.EventMetadata, data string) error {
+	dataType := n.GetDataType()
+	if dataType != collectorinterface.JSONDataType {
+		return errors.Errorf("unsupported data type %s", dataType)
+	}
+
+	subject, err := n.getSubject(metadata)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Data = []byte(data)
+	msg.Header.Set("Content-Type", n.contentType)
+	ack, err := n.jetStream.PublishMsg(msg, nats.MsgId(getMsgID(metadata)))
+	if err != nil {
+		return errors.Wrapf(err, "failed to publish %s event of volume %s to subject %s", metadata.EventType, metadata.PVName, subject)
+	}
+	klog.V(4).Infof("Published %s event of volume %s to stream %s sequence %d", metadata.EventType, metadata.PVName, ack.Stream, ack.Sequence)
+	return nil
+}
+
+// getMsgID returns the JetStream message ID of the event
+func getMsgID(metadata collectorinterface.EventMetadata) string {
+	msgID := metadata.PVUID + "-" + string(metadata.EventType)
+	if metadata.ResendID != "" {
+		msgID += "-" + metadata.ResendID
+	}
+	return msgID
+}
+
+func (n *NATSClient) getSubject(metadata collectorinterface.EventMetadata) (string, error) {
+	action, isExist := eventActions[metadata.EventType]
+	if !isExist {
+		return "", errors.Errorf("unsupported event type %q", metadata.EventType)
+	}
+	var buf bytes.Buffer
+	err := n.subjectTemplate.Execute(&buf, subjectData{EventMetadata: metadata, Action: action})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render subject of %s event of volume %s", metadata.EventType, metadata.PVName)
+	}
+	return buf.String(), nil
+}
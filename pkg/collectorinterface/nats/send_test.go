@@ -0,0 +1,224 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// fakeCollector implements VolumeEventCollector, only
+// GetDataType is used by the NATS client
+type fakeCollector struct {
+	collectorinterface.VolumeEventCollector
+	dataType collectorinterface.DataType
+}
+
+func (f *fakeCollector) GetDataType() collectorinterface.DataType {
+	return f.dataType
+}
+
+// runJetStreamServer starts an embedded NATS server with JetStream
+// enabled and creates a stream which captures all volume subjects
+func runJetStreamServer(t *testing.T) (*server.Server, *nats.Conn) {
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		t.Fatalf("NATS server is not ready for connections")
+	}
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect to NATS server: %v", err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		t.Fatalf("failed to get JetStream context: %v", err)
+	}
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:     "VOLUMES",
+		Subjects: []string{"volumes.>"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create stream: %v", err)
+	}
+	return srv, conn
+}
+
+func TestSend(t *testing.T) {
+	srv, conn := runJetStreamServer(t)
+	defer srv.Shutdown()
+	defer conn.Close()
+
+	tests := map[string]struct {
+		metadata        collectorinterface.EventMetadata
+		dataType        collectorinterface.DataType
+		subjectTemplate string
+		contentType     string
+		expectedSubject string
+		expectedMsgID   string
+		isErrExpected   bool
+	}{
+		"when create event is published": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+				CASType:   "nfs-kernel",
+			},
+			dataType:        collectorinterface.JSONDataType,
+			subjectTemplate: "volumes.{{ .CASType }}.{{ .Action }}",
+			expectedSubject: "volumes.nfs-kernel.created",
+			expectedMsgID:   "uid-1-create",
+		},
+		"when create event is resent": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+				CASType:   "nfs-kernel",
+				ResendID:  "resend-1",
+			},
+			dataType:        collectorinterface.JSONDataType,
+			subjectTemplate: "volumes.{{ .CASType }}.{{ .Action }}",
+			expectedSubject: "volumes.nfs-kernel.created",
+			expectedMsgID:   "uid-1-create-resend-1",
+		},
+		"when delete event is published": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.DeleteEventType,
+				PVName:    "pv2",
+				PVUID:     "uid-2",
+				CASType:   "nfs-kernel",
+			},
+			dataType:        collectorinterface.JSONDataType,
+			subjectTemplate: "volumes.{{ .CASType }}.{{ .Action }}",
+			contentType:     "text/csv",
+			expectedSubject: "volumes.nfs-kernel.deleted",
+			expectedMsgID:   "uid-2-delete",
+		},
+		"when subject is not captured by any stream": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.DeleteEventType,
+				PVName:    "pv3",
+				PVUID:     "uid-3",
+				CASType:   "nfs-kernel",
+			},
+			dataType:        collectorinterface.JSONDataType,
+			subjectTemplate: "unknown.{{ .CASType }}.{{ .Action }}",
+			isErrExpected:   true,
+		},
+		"when data type is not supported": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv4",
+				PVUID:     "uid-4",
+				CASType:   "nfs-kernel",
+			},
+			dataType:        collectorinterface.YAMLDataType,
+			subjectTemplate: "volumes.{{ .CASType }}.{{ .Action }}",
+			isErrExpected:   true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			contentType := test.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			publisher, err := newPublisher(conn, test.subjectTemplate, contentType)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur while creating publisher but got %v", name, err)
+			}
+			sub, err := conn.SubscribeSync(test.expectedSubject)
+			if err != nil && !test.isErrExpected {
+				t.Fatalf("%q test failed to subscribe subject %s: %v", name, test.expectedSubject, err)
+			}
+
+			client := publisher.NewNATSClient(&fakeCollector{dataType: test.dataType})
+			err = client.Send(test.metadata, `{"volume_provisioned":{}}`)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if !test.isErrExpected {
+				msg, err := sub.NextMsg(5 * time.Second)
+				if err != nil {
+					t.Fatalf("%q test failed expected message on subject %s but got %v", name, test.expectedSubject, err)
+				}
+				if msg.Header.Get(nats.MsgIdHdr) != test.expectedMsgID {
+					t.Fatalf("%q test failed unexpected message ID %s", name, msg.Header.Get(nats.MsgIdHdr))
+				}
+				if msg.Header.Get("Content-Type") != contentType {
+					t.Fatalf("%q test failed expected content type %s but got %s", name, contentType, msg.Header.Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	srv, conn := runJetStreamServer(t)
+	defer srv.Shutdown()
+	defer conn.Close()
+
+	publisherConn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect to NATS server: %v", err)
+	}
+	publisher, err := newPublisher(publisherConn, "volumes.{{ .CASType }}.{{ .Action }}", "application/json")
+	if err != nil {
+		t.Fatalf("expected error not to occur while creating publisher but got %v", err)
+	}
+	client := publisher.NewNATSClient(&fakeCollector{dataType: collectorinterface.JSONDataType})
+	err = client.Send(collectorinterface.EventMetadata{
+		EventType: collectorinterface.CreateEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+		CASType:   "nfs-kernel",
+	}, `{"volume_provisioned":{}}`)
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+
+	if err := publisher.Close(); err != nil {
+		t.Fatalf("expected error not to occur while closing publisher but got %v", err)
+	}
+	if !publisherConn.IsClosed() {
+		t.Fatalf("expected connection to be closed once Close returns")
+	}
+	// Closing again must not fail
+	if err := publisher.Close(); err != nil {
+		t.Fatalf("expected error not to occur while closing publisher again but got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/mayadata-io/volume-events-exporter/pkg/helper"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// sseS3Type encrypts objects with keys managed by object storage
+	sseS3Type = "AES256"
+	// sseKMSType encrypts objects with key managed by KMS
+	sseKMSType = "aws:kms"
+
+	// dateLayout is the layout of date component in object key
+	dateLayout = "2006-01-02"
+)
+
+// Uploader holds the object storage client. It is shared
+// across all the S3Client instances
+type Uploader struct {
+	client *minio.Client
+
+	bucket string
+
+	// clusterName is the first component of every object key
+	clusterName string
+
+	// sse is applied on every object, nil if encryption is not configured
+	sse encrypt.ServerSide
+
+	// timeout is the deadline of each PUT request
+	timeout time.Duration
+
+	// contentType of objects, it depends on the payload
+	// rendered by configured template
+	contentType string
+
+	// now returns the current time, overridden in tests
+	now func() time.Time
+}
+
+// S3Client writes volume events as objects to S3 compatible storage
+type S3Client struct {
+	*Uploader
+	// VolumeCollector implements methods required for event collector
+	collectorinterface.VolumeEventCollector
+}
+
+// NewUploader returns the uploader configured via environment variables
+func NewUploader() (*Uploader, error) {
+	endpoint := env.GetS3Endpoint()
+	if endpoint == "" {
+		return nil, errors.Errorf("object storage endpoint is not configured, set %s", env.S3Endpoint)
+	}
+	bucket := env.GetS3Bucket()
+	if bucket == "" {
+		return nil, errors.Errorf("bucket is not configured, set %s", env.S3Bucket)
+	}
+
+	sse, err := getServerSideEncryption(env.GetS3SSEType(), env.GetS3SSEKMSKeyID())
+	if err != nil {
+		return nil, err
+	}
+
+	options := &minio.Options{
+		Creds:  credentials.NewStaticV4(env.GetS3AccessKeyID(), env.GetS3SecretAccessKey(), ""),
+		Secure: env.IsS3TLSEnabled(),
+		Region: env.GetS3Region(),
+	}
+	if env.IsS3PathStyleEnabled() {
+		options.BucketLookup = minio.BucketLookupPath
+	}
+	if options.Secure && env.GetS3TLSCAFile() != "" {
+		tlsConfig, err := helper.NewTLSConfig(env.GetS3TLSCAFile(), "", "", false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to build object storage TLS configuration")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		options.Transport = transport
+	}
+
+	client, err := minio.New(endpoint, options)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create object storage client for %s", endpoint)
+	}
+	return newUploader(client, bucket, env.GetS3ClusterName(), sse, env.GetS3Timeout(), env.GetS3ContentType()), nil
+}
+
+func newUploader(
+	client *minio.Client,
+	bucket, clusterName string,
+	sse encrypt.ServerSide,
+	timeout time.Duration,
+	contentType string) *Uploader {
+	return &Uploader{
+		client:      client,
+		bucket:      bucket,
+		clusterName: clusterName,
+		sse:         sse,
+		timeout:     timeout,
+		contentType: contentType,
+		now:         time.Now,
+	}
+}
+
+// getServerSideEncryption returns the encryption applied on
+// objects for given type, nil if type is empty
+func getServerSideEncryption(sseType, kmsKeyID string) (encrypt.ServerSide, error) {
+	switch sseType {
+	case "":
+		return nil, nil
+	case sseS3Type:
+		return encrypt.NewSSE(), nil
+	case sseKMSType:
+		if kmsKeyID == "" {
+			return nil, errors.Errorf("KMS key is not configured, set %s", env.S3SSEKMSKeyID)
+		}
+		sse, err := encrypt.NewSSEKMS(kmsKeyID, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to configure KMS encryption")
+		}
+		return sse, nil
+	}
+	return nil, errors.Errorf("unsupported server side encryption type %q", sseType)
+}
+
+// NewS3Client returns events sender which writes events
+// collected by given collector to object storage
+func (u *Uploader) NewS3Client(collectorInterface collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	return &S3Client{
+		Uploader:             u,
+		VolumeEventCollector: collectorInterface,
+	}
+}
+
+// Send writes the data as an object with key
+// <cluster>/<date>/<pv-uid>/<event-type>.json. Event is
+// treated as delivered only if PUT request succeeds
+func (s *S3Client) Send(metadata collectorinterface.EventMetadata, data string) error {
+	dataType := s.GetDataType()
+	if dataType != collectorinterface.JSONDataType {
+		return errors.Errorf("unsupported data type %s", dataType)
+	}
+
+	key := s.objectKey(metadata)
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	_, err := s.client.PutObject(ctx, s.bucket, key, strings.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType:          s.contentType,
+		ServerSideEncryption: s.sse,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s event of volume %s to %s/%s", metadata.EventType, metadata.PVName, s.bucket, key)
+	}
+	klog.V(4).Infof("Written %s event of volume %s to %s/%s", metadata.EventType, metadata.PVName, s.bucket, key)
+	return nil
+}
+
+// objectKey returns the key of object for given event. Date is derived
+// from the time of event so that retries and resends of an event write
+// the same object. Current time is used if time of event is not known
+func (u *Uploader) objectKey(metadata collectorinterface.EventMetadata) string {
+	eventTime := metadata.Timestamp
+	if eventTime.IsZero() {
+		eventTime = u.now()
+	}
+	return path.Join(
+		u.clusterName,
+		eventTime.UTC().Format(dateLayout),
+		metadata.PVUID,
+		string(metadata.EventType)+".json")
+}
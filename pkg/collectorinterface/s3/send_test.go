@@ -0,0 +1,266 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+// streamingPayload is the content hash of aws-chunked payload
+const streamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+
+// fakeObjectStorage is a minimal S3 compatible stand-in which
+// accepts PUT requests on path style URLs of known buckets
+type fakeObjectStorage struct {
+	mutex   sync.Mutex
+	buckets map[string]bool
+	// objects holds the content of objects by request path
+	objects map[string]string
+	// headers holds the request headers of objects by request path
+	headers map[string]http.Header
+}
+
+func newFakeObjectStorage(buckets ...string) *fakeObjectStorage {
+	f := &fakeObjectStorage{
+		buckets: map[string]bool{},
+		objects: map[string]string{},
+		headers: map[string]http.Header{},
+	}
+	for _, bucket := range buckets {
+		f.buckets[bucket] = true
+	}
+	return f
+}
+
+func (f *fakeObjectStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	bucket := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)[0]
+	if r.Method != http.MethodPut || !f.buckets[bucket] {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`))
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	if r.Header.Get("X-Amz-Content-Sha256") == streamingPayload {
+		body = decodeChunkedPayload(body)
+	}
+	f.objects[r.URL.Path] = string(body)
+	f.headers[r.URL.Path] = r.Header
+	w.Header().Set("ETag", "\"d41d8cd98f00b204e9800998ecf8427e\"")
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeChunkedPayload strips the chunk headers of aws-chunked payload
+// which is of format <hex-size>;chunk-signature=<signature>\r\n<data>\r\n
+func decodeChunkedPayload(body []byte) []byte {
+	var data []byte
+	for len(body) > 0 {
+		index := bytes.Index(body, []byte("\r\n"))
+		if index < 0 {
+			break
+		}
+		size, err := strconv.ParseInt(strings.SplitN(string(body[:index]), ";", 2)[0], 16, 64)
+		if err != nil || size == 0 {
+			break
+		}
+		body = body[index+2:]
+		data = append(data, body[:size]...)
+		body = body[size+2:]
+	}
+	return data
+}
+
+// fakeCollector implements VolumeEventCollector, only
+// GetDataType is used by the S3 client
+type fakeCollector struct {
+	collectorinterface.VolumeEventCollector
+}
+
+func (f *fakeCollector) GetDataType() collectorinterface.DataType {
+	return collectorinterface.JSONDataType
+}
+
+func TestSend(t *testing.T) {
+	objectStorage := newFakeObjectStorage("events")
+	server := httptest.NewServer(objectStorage)
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	client, err := minio.New(serverURL.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4("access", "secret", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatalf("failed to create object storage client: %v", err)
+	}
+	now := func() time.Time { return time.Date(2021, 10, 20, 23, 0, 0, 0, time.UTC) }
+
+	tests := map[string]struct {
+		bucket         string
+		sse            encrypt.ServerSide
+		contentType    string
+		metadata       collectorinterface.EventMetadata
+		expectedPath   string
+		expectedHeader map[string]string
+		isErrExpected  bool
+	}{
+		"when create event is written": {
+			bucket: "events",
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+				Timestamp: time.Date(2021, 10, 18, 10, 0, 0, 0, time.UTC),
+			},
+			expectedPath: "/events/cluster1/2021-10-18/uid-1/create.json",
+		},
+		"when delete event is written with server side encryption": {
+			bucket: "events",
+			sse:    encrypt.NewSSE(),
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.DeleteEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-1",
+				Timestamp: time.Date(2021, 10, 19, 23, 30, 0, 0, time.FixedZone("UTC-1", -3600)),
+			},
+			expectedPath:   "/events/cluster1/2021-10-20/uid-1/delete.json",
+			expectedHeader: map[string]string{"X-Amz-Server-Side-Encryption": "AES256"},
+		},
+		"when time of event is not known": {
+			bucket:      "events",
+			contentType: "text/csv",
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv3",
+				PVUID:     "uid-3",
+			},
+			expectedPath: "/events/cluster1/2021-10-20/uid-3/create.json",
+		},
+		"when PUT request is rejected": {
+			bucket: "unknown",
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv2",
+				PVUID:     "uid-2",
+			},
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			contentType := test.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			uploader := newUploader(client, test.bucket, "cluster1", test.sse, 10*time.Second, contentType)
+			uploader.now = now
+			data := `{"pv":"` + test.metadata.PVName + `"}`
+			err := uploader.NewS3Client(&fakeCollector{}).Send(test.metadata, data)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if test.isErrExpected {
+				return
+			}
+
+			objectStorage.mutex.Lock()
+			defer objectStorage.mutex.Unlock()
+			object, ok := objectStorage.objects[test.expectedPath]
+			if !ok {
+				t.Fatalf("%q test failed expected object %s to exist but got %v", name, test.expectedPath, objectStorage.objects)
+			}
+			if object != data {
+				t.Fatalf("%q test failed expected object content %q but got %q", name, data, object)
+			}
+			if got := objectStorage.headers[test.expectedPath].Get("Content-Type"); got != contentType {
+				t.Fatalf("%q test failed expected content type %q but got %q", name, contentType, got)
+			}
+			for key, value := range test.expectedHeader {
+				if got := objectStorage.headers[test.expectedPath].Get(key); got != value {
+					t.Fatalf("%q test failed expected header %s to be %q but got %q", name, key, value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGetServerSideEncryption(t *testing.T) {
+	tests := map[string]struct {
+		sseType       string
+		kmsKeyID      string
+		isNilExpected bool
+		isErrExpected bool
+	}{
+		"when encryption is not configured": {
+			isNilExpected: true,
+		},
+		"when encryption is managed by object storage": {
+			sseType: "AES256",
+		},
+		"when encryption is managed by KMS": {
+			sseType:  "aws:kms",
+			kmsKeyID: "key1",
+		},
+		"when KMS key is missing": {
+			sseType:       "aws:kms",
+			isErrExpected: true,
+		},
+		"when encryption type is not supported": {
+			sseType:       "unknown",
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			sse, err := getServerSideEncryption(test.sseType, test.kmsKeyID)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if !test.isErrExpected && test.isNilExpected != (sse == nil) {
+				t.Fatalf("%q test failed expected encryption to be nil(%t) but got %v", name, test.isNilExpected, sse)
+			}
+		})
+	}
+}
@@ -54,7 +54,7 @@ func NewTokenClient(collectorInterface collectorinterface.VolumeEventCollector)
 	}
 }
 
-func (d *TokenClient) Send(data string) error {
+func (d *TokenClient) Send(_ collectorinterface.EventMetadata, data string) error {
 	var payload []byte
 	var contentType string
 
@@ -17,22 +17,57 @@ limitations under the License.
 package collectorinterface
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 )
 
 type EventsSender interface {
 	// Send will push given event information to configured server
 	// NOTE: Send should convert data into required format before sending
-	//		 to server
-	Send(data string) error
+	//		 to server. Send must return only after the data is durably
+	//		 accepted by the sink since resources are annotated and finalizers
+	//		 are removed right after Send returns
+	Send(metadata EventMetadata, data string) error
 	VolumeEventCollector
 }
 
+// EventsSenderBuilder returns an EventsSender which pushes events
+// collected by given collector to the configured sink
+type EventsSenderBuilder func(collector VolumeEventCollector) EventsSender
+
+// EventMetadata holds the information about the event being sent.
+// Sinks can make use of it to route or key the event data
+type EventMetadata struct {
+	// EventType states whether the event is a create or delete event
+	EventType EventType
+	// PVName is the name of the PersistentVolume
+	PVName string
+	// PVUID is the UID of the PersistentVolume
+	PVUID string
+	// CASType is the type of the volume(ex: nfs-kernel)
+	CASType string
+	// Origin states how the event is generated, it is empty for
+	// events generated from the lifecycle of the volume
+	Origin string
+	// Timestamp is the time at which event occurred i.e creation time
+	// of volume for create event and deletion time for delete event
+	Timestamp time.Time
+	// ResendID is unique for every on demand resend of the event, it
+	// is empty otherwise. Sinks which drop duplicates of an event must
+	// include it in the identity of the event
+	ResendID string
+}
+
 type VolumeEventCollector interface {
 	// CollectCreateEvents should return data required for volume create event
 	CollectCreateEvents() (string, error)
 	// CollectDeleteEvents should return data required for volume delete event
 	CollectDeleteEvents() (string, error)
+	// AddEventFinalizer should add the finalizer on PersistentVolume and all dependent
+	// resources which are not yet marked for deletion. It returns the updated
+	// PersistentVolume object
+	AddEventFinalizer(pvObj *corev1.PersistentVolume) (*corev1.PersistentVolume, error)
 	// RemoveEventFinalizer should remove the finalizer on all dependent resources
 	RemoveEventFinalizer() error
 	// AnnotateCreateEvent will set create event annotation on PersistentVolume object
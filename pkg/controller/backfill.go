@@ -0,0 +1,220 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/helper"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+)
+
+// BackfillOptions selects the volumes to backfill and
+// controls the rate of create events
+type BackfillOptions struct {
+	// StorageClasses are the names of StorageClasses whose volumes are
+	// backfilled. Volumes of all StorageClasses are backfilled if empty
+	StorageClasses []string
+	// RateLimiter bounds the rate of create events sent
+	RateLimiter flowcontrol.RateLimiter
+	// DryRun only lists the volumes to backfill
+	DryRun bool
+}
+
+// BackfillSummary reports the outcome of backfilling volumes
+type BackfillSummary struct {
+	// Volumes holds the volumes matching backfill options. Create
+	// events of these volumes are sent unless it is a dry run
+	Volumes []*corev1.PersistentVolume
+	// Failed holds the volumes whose create event couldn't be sent or
+	// which couldn't be opted-in. Event finalizers of these volumes are
+	// rolled back, error states if rollback is failed
+	Failed map[string]error
+}
+
+// Backfill sends create events of the volumes which were provisioned
+// before volume events are enabled. Events are marked as
+// synthetic/backfilled and volumes are opted-in to volume events
+// once create event is sent so that delete event is sent by controller
+func (pController *PVEventController) Backfill(options BackfillOptions) (*BackfillSummary, error) {
+	pvList, err := pController.pvLister.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list PVs")
+	}
+	sort.Slice(pvList, func(i, j int) bool { return pvList[i].Name < pvList[j].Name })
+
+	summary := &BackfillSummary{
+		Failed: map[string]error{},
+	}
+	for _, pvObj := range pvList {
+		if isBackfillRequired(pvObj, options.StorageClasses) {
+			summary.Volumes = append(summary.Volumes, pvObj.DeepCopy())
+		}
+	}
+	if options.DryRun {
+		return summary, nil
+	}
+
+	for _, pvObj := range summary.Volumes {
+		options.RateLimiter.Accept()
+		err := pController.backfillVolume(pvObj.Name, options.StorageClasses)
+		if err != nil {
+			klog.Errorf("Failed to backfill volume %s: %v", pvObj.Name, err)
+			summary.Failed[pvObj.Name] = err
+		}
+	}
+	return summary, nil
+}
+
+// backfillVolume sends synthetic create event of the volume and then
+// opts-in the volume to volume events
+func (pController *PVEventController) backfillVolume(name string, storageClasses []string) error {
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	// Volume might have been opted-in or deleted meanwhile
+	if !isBackfillRequired(pvObj, storageClasses) {
+		return nil
+	}
+
+	// Step1: Mark the origin first so that the collected data states that
+	// event is synthetic. Controller ignores volume till it is opted-in
+	pvObj, err = pController.patchVolumeAnnotation(pvObj,
+		collectorinterface.VolumeEventOriginAnnotation, collectorinterface.BackfilledEventOrigin)
+	if err != nil {
+		return err
+	}
+
+	// Step2: Add finalizers & send create event. Finalizers are rolled back
+	// on failure since controller ignores the volume till it is opted-in,
+	// hence finalizers would block the deletion of volume forever
+	eventSender, err := pController.getEventSender(pvObj)
+	if err != nil {
+		return err
+	}
+	pvObj, err = eventSender.AddEventFinalizer(pvObj)
+	if err != nil {
+		return pController.rollbackBackfill(name, errors.Wrapf(err, "failed to add finalizers on volume %s", name))
+	}
+	err = pController.sendCreateEvent(eventSender, pvObj)
+	if err != nil {
+		return pController.rollbackBackfill(name, err)
+	}
+
+	// Step3: Opt-in volume once create event is sent so that
+	// controller will not send create event once again
+	pvObj, err = pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err == nil {
+		_, err = pController.patchVolumeAnnotation(pvObj, annotationProcessEventKey, eventRequiredAnnotationValue)
+	}
+	if err != nil {
+		return pController.rollbackBackfill(name,
+			errors.Wrapf(err, "create event of volume %s is sent but volume is not opted-in, delete event will not be sent", name))
+	}
+	return nil
+}
+
+// rollbackBackfill removes the event finalizers and origin annotation added
+// while backfilling the volume. Origin is retained if create event is already
+// sent. Returned error reports the failure of backfill along with rollback
+func (pController *PVEventController) rollbackBackfill(name string, backfillErr error) error {
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return backfillErr
+		}
+		return errors.Errorf("%v, failed to roll back event finalizers: %v", backfillErr, err)
+	}
+	// Volume is fetched again since finalizers & annotations are updated
+	eventSender, err := pController.getEventSender(pvObj)
+	if err == nil {
+		err = eventSender.RemoveEventFinalizer()
+	}
+	if err != nil {
+		return errors.Errorf("%v, failed to roll back event finalizers: %v", backfillErr, err)
+	}
+
+	if _, isOriginExist := pvObj.Annotations[collectorinterface.VolumeEventOriginAnnotation]; isOriginExist && !isCreateVolumeEventSent(pvObj) {
+		pvObj, err = pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil {
+			pvCopy := pvObj.DeepCopy()
+			delete(pvCopy.Annotations, collectorinterface.VolumeEventOriginAnnotation)
+			_, err = pController.patchVolume(pvObj, pvCopy)
+		}
+		if err != nil {
+			return errors.Errorf("%v, rolled back event finalizers but failed to remove origin annotation: %v", backfillErr, err)
+		}
+	}
+	return errors.Wrapf(backfillErr, "rolled back event finalizers")
+}
+
+// patchVolumeAnnotation sets the given annotation on volume
+func (pController *PVEventController) patchVolumeAnnotation(pvObj *corev1.PersistentVolume, key, value string) (*corev1.PersistentVolume, error) {
+	pvCopy := pvObj.DeepCopy()
+	if pvCopy.Annotations == nil {
+		pvCopy.Annotations = map[string]string{}
+	}
+	pvCopy.Annotations[key] = value
+	newPVObj, err := pController.patchVolume(pvObj, pvCopy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to annotate volume %s with %s", pvObj.Name, key)
+	}
+	return newPVObj, nil
+}
+
+// patchVolume patches the changes made on pvCopy to the volume
+func (pController *PVEventController) patchVolume(pvObj, pvCopy *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	patchBytes, _, err := helper.GetPatchData(pvObj, pvCopy)
+	if err != nil {
+		return nil, err
+	}
+	return pController.kubeClientset.CoreV1().
+		PersistentVolumes().
+		Patch(context.TODO(), pvObj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+}
+
+// isBackfillRequired returns true if volume of given StorageClasses is
+// not yet opted-in to volume events and create event is not yet sent
+func isBackfillRequired(pvObj *corev1.PersistentVolume, storageClasses []string) bool {
+	// Only NFS volumes supports volume events
+	if pvObj.DeletionTimestamp != nil || getCASType(pvObj) != nfspv.OpenEBSNFSCASLabelValue {
+		return false
+	}
+	if pvObj.Annotations[annotationProcessEventKey] == eventRequiredAnnotationValue || isCreateVolumeEventSent(pvObj) {
+		return false
+	}
+	if len(storageClasses) == 0 {
+		return true
+	}
+	for _, storageClass := range storageClasses {
+		if pvObj.Spec.StorageClassName == storageClass {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func TestBackfill(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	newPV := func(name, casType, storageClass string, annotations map[string]string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: annotations,
+				Labels:      map[string]string{OpenEBSCASLabelKey: casType},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName: storageClass,
+			},
+		}
+	}
+	deletedPV := newPV("pv5", nfspv.OpenEBSNFSCASLabelValue, "openebs-rwx", nil)
+	deletedPV.DeletionTimestamp = &deletionTimestamp
+	pvObjs := []*corev1.PersistentVolume{
+		newPV("pv1", nfspv.OpenEBSNFSCASLabelValue, "openebs-rwx", nil),
+		newPV("pv2", nfspv.OpenEBSNFSCASLabelValue, "openebs-kernel-nfs", nil),
+		// Volume which is already opted-in
+		newPV("pv3", nfspv.OpenEBSNFSCASLabelValue, "openebs-rwx", map[string]string{"events.openebs.io/required": "true"}),
+		newPV("pv4", "local-hostpath", "openebs-rwx", nil),
+		deletedPV,
+	}
+	tests := map[string]struct {
+		options BackfillOptions
+		sendErr error
+		// optInErr is returned while opting-in the volume
+		optInErr         error
+		expectedVolumes  []string
+		expectedFailed   int
+		expectedSent     int
+		expectedReleased int
+	}{
+		"when volumes of StorageClass are listed in dry run": {
+			options: BackfillOptions{
+				StorageClasses: []string{"openebs-rwx"},
+				DryRun:         true,
+			},
+			expectedVolumes: []string{"pv1"},
+		},
+		"when volumes of all StorageClasses are backfilled": {
+			expectedVolumes: []string{"pv1", "pv2"},
+			expectedSent:    2,
+		},
+		"when create events couldn't be delivered": {
+			sendErr:          errors.Errorf("server not reachable"),
+			expectedVolumes:  []string{"pv1", "pv2"},
+			expectedFailed:   2,
+			expectedReleased: 2,
+		},
+		"when volumes couldn't be opted-in": {
+			optInErr:         errors.Errorf("etcd is unavailable"),
+			expectedVolumes:  []string{"pv1", "pv2"},
+			expectedFailed:   2,
+			expectedSent:     2,
+			expectedReleased: 2,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			objs := make([]runtime.Object, 0, len(pvObjs))
+			for _, pvObj := range pvObjs {
+				if err := indexer.Add(pvObj); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+				objs = append(objs, pvObj.DeepCopy())
+			}
+			kubeClient := fake.NewSimpleClientset(objs...)
+			if test.optInErr != nil {
+				kubeClient.PrependReactor("patch", "persistentvolumes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					if strings.Contains(string(action.(k8stesting.PatchAction).GetPatch()), annotationProcessEventKey) {
+						return true, nil, test.optInErr
+					}
+					return false, nil, nil
+				})
+			}
+			var sent []collectorinterface.EventMetadata
+			var released int
+			pController := &PVEventController{
+				controller:    newController("test", 1),
+				kubeClientset: kubeClient,
+				pvLister:      corev1listers.NewPersistentVolumeLister(indexer),
+				recorder:      &Recorder{EventRecorder: record.NewFakeRecorder(100)},
+				eventsSenderBuilder: func(collector collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+					return &fakeEventsSender{
+						VolumeEventCollector: collector,
+						sendErr:              test.sendErr,
+						sent:                 &sent,
+						released:             &released,
+					}
+				},
+			}
+			test.options.RateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
+
+			summary, err := pController.Backfill(test.options)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if len(summary.Volumes) != len(test.expectedVolumes) {
+				t.Fatalf("%q test failed expected volumes %v but got %d volumes", name, test.expectedVolumes, len(summary.Volumes))
+			}
+			for i, pvObj := range summary.Volumes {
+				if pvObj.Name != test.expectedVolumes[i] {
+					t.Errorf("%q test failed expected volumes %v but got %s at %d", name, test.expectedVolumes, pvObj.Name, i)
+				}
+			}
+			if len(summary.Failed) != test.expectedFailed {
+				t.Errorf("%q test failed expected %d failed volumes but got %v", name, test.expectedFailed, summary.Failed)
+			}
+
+			if len(sent) != test.expectedSent {
+				t.Fatalf("%q test failed expected %d events to be sent but got %v", name, test.expectedSent, sent)
+			}
+			if released != test.expectedReleased {
+				t.Errorf("%q test failed expected finalizers of %d volumes to be rolled back but got %d", name, test.expectedReleased, released)
+			}
+			for _, metadata := range sent {
+				if metadata.EventType != collectorinterface.CreateEventType || metadata.Origin != collectorinterface.BackfilledEventOrigin {
+					t.Errorf("%q test failed expected backfilled create event but got %v", name, metadata)
+				}
+			}
+
+			for _, pvName := range test.expectedVolumes {
+				pvObj, err := kubeClient.CoreV1().PersistentVolumes().Get(context.TODO(), pvName, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+				_, isFailed := summary.Failed[pvName]
+				isOptInExpected := !test.options.DryRun && !isFailed
+				isOptedIn := pvObj.Annotations[annotationProcessEventKey] == eventRequiredAnnotationValue
+				if isOptedIn != isOptInExpected {
+					t.Errorf("%q test failed expected volume %s opted-in %t but got annotations %v", name, pvName, isOptInExpected, pvObj.Annotations)
+				}
+				// Origin of failed volume is rolled back since fake
+				// sender doesn't annotate the volume with create event
+				_, isOriginExist := pvObj.Annotations[collectorinterface.VolumeEventOriginAnnotation]
+				if isOriginExist != isOptInExpected {
+					t.Errorf("%q test failed expected origin annotation on volume %s %t but got annotations %v",
+						name, pvName, isOptInExpected, pvObj.Annotations)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/helper"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DeliveryStatusAnnotationKey holds the JSON encoded DeliveryStatus
+	// of volume events on opted-in volumes
+	DeliveryStatusAnnotationKey = "events.openebs.io/delivery-status"
+
+	// maxLastErrorSummaryLength is the maximum length of error in status
+	maxLastErrorSummaryLength = 256
+
+	// retryBaseDelay and retryMaxDelay are the per item exponential
+	// backoff of workqueue.DefaultControllerRateLimiter
+	retryBaseDelay = 5 * time.Millisecond
+	retryMaxDelay  = 1000 * time.Second
+)
+
+// DeliveryPhase represents the progress of volume events of a volume
+type DeliveryPhase string
+
+const (
+	// PendingCreatePhase states that create event is not yet sent
+	PendingCreatePhase DeliveryPhase = "pending-create"
+	// CreatedPhase states that create event is sent and volume is not yet deleted
+	CreatedPhase DeliveryPhase = "created"
+	// PendingDeletePhase states that delete event is not yet sent or
+	// finalizers are not yet removed from volume resources
+	PendingDeletePhase DeliveryPhase = "pending-delete"
+	// FinalizerReleasedPhase states that delete event is sent and
+	// event finalizers are removed from volume resources
+	FinalizerReleasedPhase DeliveryPhase = "finalizer-released"
+)
+
+// DeliveryStatus holds the delivery status of volume events
+type DeliveryStatus struct {
+	Phase DeliveryPhase `json:"phase"`
+	// Attempts is the number of failed attempts in current phase, it
+	// is reset once volume moves to next phase
+	Attempts        int          `json:"attempts"`
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+	// LastError is the summary of error of last failed attempt
+	LastError     string       `json:"lastError,omitempty"`
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+}
+
+// GetDeliveryStatus returns the delivery status of volume from annotation.
+// It returns nil if annotation doesn't exist
+func GetDeliveryStatus(pvObj *corev1.PersistentVolume) (*DeliveryStatus, error) {
+	value, isExist := pvObj.Annotations[DeliveryStatusAnnotationKey]
+	if !isExist {
+		return nil, nil
+	}
+	status := &DeliveryStatus{}
+	err := json.Unmarshal([]byte(value), status)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s annotation of volume %s", DeliveryStatusAnnotationKey, pvObj.Name)
+	}
+	return status, nil
+}
+
+// updateDeliveryStatus updates the delivery status annotation of volume
+// after an attempt to send volume events. syncErr is the error of attempt
+func (pController *PVEventController) updateDeliveryStatus(key, name string, syncErr error) {
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		// Volume will not exist once event finalizers are released
+		if !k8serror.IsNotFound(err) {
+			klog.Errorf("Failed to get PV %s to update delivery status: %v", name, err)
+		}
+		return
+	}
+
+	oldStatus, err := GetDeliveryStatus(pvObj)
+	if err != nil {
+		// Invalid status will be overwritten
+		klog.Warningf("%v", err)
+	}
+	// Volume might be held by other finalizers after event finalizers are
+	// released, it is not required to update status of such volume
+	if oldStatus != nil && oldStatus.Phase == FinalizerReleasedPhase {
+		return
+	}
+	now := metav1.Now()
+	var nextRetryTime *metav1.Time
+	if syncErr != nil {
+		retryTime := metav1.NewTime(now.Add(pController.getRetryDelay(key)))
+		nextRetryTime = &retryTime
+	}
+	status := newDeliveryStatus(oldStatus, getDeliveryPhase(pvObj), now, nextRetryTime, syncErr)
+	// Attempt time alone is not updated to avoid writes on every reconcile
+	if isDeliveryStatusUnchanged(oldStatus, status) {
+		return
+	}
+
+	value, err := json.Marshal(status)
+	if err != nil {
+		klog.Errorf("Failed to encode delivery status of PV %s: %v", name, err)
+		return
+	}
+	pvCopy := pvObj.DeepCopy()
+	if pvCopy.Annotations == nil {
+		pvCopy.Annotations = make(map[string]string)
+	}
+	pvCopy.Annotations[DeliveryStatusAnnotationKey] = string(value)
+	patchBytes, _, err := helper.GetPatchData(pvObj, pvCopy)
+	if err != nil {
+		klog.Errorf("Failed to build patch of delivery status of PV %s: %v", name, err)
+		return
+	}
+	_, err = pController.kubeClientset.CoreV1().
+		PersistentVolumes().
+		Patch(context.TODO(), name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		klog.Errorf("Failed to update delivery status of PV %s: %v", name, err)
+	}
+}
+
+// newDeliveryStatus returns the status of volume in given phase. Failed
+// attempts are carried forward from old status if phase is not changed
+func newDeliveryStatus(
+	oldStatus *DeliveryStatus,
+	phase DeliveryPhase,
+	attemptTime metav1.Time,
+	nextRetryTime *metav1.Time,
+	syncErr error) *DeliveryStatus {
+	status := &DeliveryStatus{
+		Phase:           phase,
+		LastAttemptTime: &attemptTime,
+	}
+	if oldStatus != nil && oldStatus.Phase == phase {
+		status.Attempts = oldStatus.Attempts
+	}
+	if syncErr != nil {
+		status.Attempts++
+		status.LastError = summarizeError(syncErr)
+		status.NextRetryTime = nextRetryTime
+	}
+	return status
+}
+
+// isDeliveryStatusUnchanged returns true if phase, attempts and
+// error of delivery status are not changed
+func isDeliveryStatusUnchanged(oldStatus, newStatus *DeliveryStatus) bool {
+	return oldStatus != nil &&
+		oldStatus.Phase == newStatus.Phase &&
+		oldStatus.Attempts == newStatus.Attempts &&
+		oldStatus.LastError == newStatus.LastError
+}
+
+// getDeliveryPhase returns the phase of volume based on event
+// annotations, deletion timestamp and finalizers of volume
+func getDeliveryPhase(pvObj *corev1.PersistentVolume) DeliveryPhase {
+	if !isCreateVolumeEventSent(pvObj) {
+		return PendingCreatePhase
+	}
+	if pvObj.DeletionTimestamp == nil {
+		return CreatedPhase
+	}
+	if !isDeleteVolumeEventSent(pvObj) {
+		return PendingDeletePhase
+	}
+	for _, finalizer := range pvObj.Finalizers {
+		if strings.HasSuffix(finalizer, collectorinterface.VolumeEventsFinalizer) {
+			return PendingDeletePhase
+		}
+	}
+	return FinalizerReleasedPhase
+}
+
+// getRetryDelay returns the delay after which failed volume is
+// retried. Volumes are also retried on every resync of informer.
+// It is called before the failed volume is requeued, hence the
+// upcoming requeue is also accounted
+func (pController *PVEventController) getRetryDelay(key string) time.Duration {
+	delay := retryMaxDelay
+	backoff := float64(retryBaseDelay) * math.Pow(2, float64(pController.workQueue.NumRequeues(key)+1))
+	if backoff < float64(retryMaxDelay) {
+		delay = time.Duration(backoff)
+	}
+	if resyncInterval := GetSyncInterval(); resyncInterval < delay {
+		delay = resyncInterval
+	}
+	return delay
+}
+
+// isOnlyDeliveryStatusUpdated returns true if delivery status annotation
+// is the only change between given volumes. Such updates are made by
+// controller itself and must not bypass the backoff of failed volumes
+func isOnlyDeliveryStatusUpdated(oldPV, newPV *corev1.PersistentVolume) bool {
+	if oldPV.Annotations[DeliveryStatusAnnotationKey] == newPV.Annotations[DeliveryStatusAnnotationKey] {
+		return false
+	}
+	oldCopy, newCopy := oldPV.DeepCopy(), newPV.DeepCopy()
+	for _, pvObj := range []*corev1.PersistentVolume{oldCopy, newCopy} {
+		delete(pvObj.Annotations, DeliveryStatusAnnotationKey)
+		pvObj.ResourceVersion = ""
+		pvObj.ManagedFields = nil
+	}
+	return equality.Semantic.DeepEqual(oldCopy, newCopy)
+}
+
+// summarizeError returns the first line of error truncated
+// to maxLastErrorSummaryLength
+func summarizeError(err error) string {
+	summary := strings.SplitN(err.Error(), "\n", 2)[0]
+	if len(summary) > maxLastErrorSummaryLength {
+		summary = summary[:maxLastErrorSummaryLength]
+	}
+	return summary
+}
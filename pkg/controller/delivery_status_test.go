@@ -0,0 +1,329 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetDeliveryPhase(t *testing.T) {
+	deletionTime := metav1.Now()
+	tests := map[string]struct {
+		pvObj         *corev1.PersistentVolume
+		expectedPhase DeliveryPhase
+	}{
+		"when create event is not yet sent": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv1"},
+			},
+			expectedPhase: PendingCreatePhase,
+		},
+		"when create event is sent": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pv2",
+					Annotations: map[string]string{"nfs.event.openebs.io/volume-create": "sent"},
+				},
+			},
+			expectedPhase: CreatedPhase,
+		},
+		"when volume is deleted and delete event is not yet sent": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv3",
+					DeletionTimestamp: &deletionTime,
+					Annotations:       map[string]string{"nfs.event.openebs.io/volume-create": "sent"},
+				},
+			},
+			expectedPhase: PendingDeletePhase,
+		},
+		"when delete event is sent and finalizer is not yet removed": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv4",
+					DeletionTimestamp: &deletionTime,
+					Finalizers:        []string{"nfs.events.openebs.io/finalizer"},
+					Annotations: map[string]string{
+						"nfs.event.openebs.io/volume-create": "sent",
+						"nfs.event.openebs.io/volume-delete": "sent",
+					},
+				},
+			},
+			expectedPhase: PendingDeletePhase,
+		},
+		"when delete event is sent and finalizer is removed": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv5",
+					DeletionTimestamp: &deletionTime,
+					Finalizers:        []string{"kubernetes.io/pv-protection"},
+					Annotations: map[string]string{
+						"nfs.event.openebs.io/volume-create": "sent",
+						"nfs.event.openebs.io/volume-delete": "sent",
+					},
+				},
+			},
+			expectedPhase: FinalizerReleasedPhase,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			phase := getDeliveryPhase(test.pvObj)
+			if phase != test.expectedPhase {
+				t.Errorf("%q test failed expected phase %s but got %s", name, test.expectedPhase, phase)
+			}
+		})
+	}
+}
+
+func TestNewDeliveryStatus(t *testing.T) {
+	attemptTime := metav1.NewTime(time.Date(2021, time.October, 1, 10, 0, 0, 0, time.UTC))
+	retryTime := metav1.NewTime(attemptTime.Add(time.Minute))
+	tests := map[string]struct {
+		oldStatus      *DeliveryStatus
+		phase          DeliveryPhase
+		syncErr        error
+		expectedStatus *DeliveryStatus
+	}{
+		"when first attempt is failed": {
+			phase:   PendingCreatePhase,
+			syncErr: errors.New("connection refused\nretry later"),
+			expectedStatus: &DeliveryStatus{
+				Phase:           PendingCreatePhase,
+				Attempts:        1,
+				LastAttemptTime: &attemptTime,
+				LastError:       "connection refused",
+				NextRetryTime:   &retryTime,
+			},
+		},
+		"when attempt is failed again in same phase": {
+			oldStatus: &DeliveryStatus{Phase: PendingCreatePhase, Attempts: 2},
+			phase:     PendingCreatePhase,
+			syncErr:   errors.New("connection refused"),
+			expectedStatus: &DeliveryStatus{
+				Phase:           PendingCreatePhase,
+				Attempts:        3,
+				LastAttemptTime: &attemptTime,
+				LastError:       "connection refused",
+				NextRetryTime:   &retryTime,
+			},
+		},
+		"when volume moved to next phase": {
+			oldStatus: &DeliveryStatus{Phase: PendingCreatePhase, Attempts: 2, LastError: "connection refused"},
+			phase:     CreatedPhase,
+			expectedStatus: &DeliveryStatus{
+				Phase:           CreatedPhase,
+				LastAttemptTime: &attemptTime,
+			},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			status := newDeliveryStatus(test.oldStatus, test.phase, attemptTime, &retryTime, test.syncErr)
+			if !cmp.Equal(status, test.expectedStatus) {
+				t.Errorf("%q test failed expected no diff but got \n%s", name, cmp.Diff(test.expectedStatus, status))
+			}
+		})
+	}
+}
+
+func TestIsOnlyDeliveryStatusUpdated(t *testing.T) {
+	oldPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "pv1",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{DeliveryStatusAnnotationKey: `{"phase":"pending-create","attempts":1}`},
+		},
+	}
+	tests := map[string]struct {
+		newPV          *corev1.PersistentVolume
+		expectedResult bool
+	}{
+		"when only delivery status is updated": {
+			newPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "pv1",
+					ResourceVersion: "2",
+					Annotations:     map[string]string{DeliveryStatusAnnotationKey: `{"phase":"pending-create","attempts":2}`},
+				},
+			},
+			expectedResult: true,
+		},
+		"when volume is resynced": {
+			newPV:          oldPV.DeepCopy(),
+			expectedResult: false,
+		},
+		"when volume is marked for deletion": {
+			newPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv1",
+					ResourceVersion:   "2",
+					DeletionTimestamp: func() *metav1.Time { t := metav1.Now(); return &t }(),
+					Annotations:       map[string]string{DeliveryStatusAnnotationKey: `{"phase":"pending-create","attempts":2}`},
+				},
+			},
+			expectedResult: false,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			result := isOnlyDeliveryStatusUpdated(oldPV, test.newPV)
+			if result != test.expectedResult {
+				t.Errorf("%q test failed expected %t but got %t", name, test.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUpdateDeliveryStatus(t *testing.T) {
+	pvObj := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pv1",
+			Annotations: map[string]string{
+				"events.openebs.io/required": "true",
+				DeliveryStatusAnnotationKey:  `{"phase":"pending-create","attempts":1}`,
+			},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(pvObj)
+	pController := &PVEventController{
+		controller:    newController("test", 1),
+		kubeClientset: kubeClient,
+	}
+
+	pController.updateDeliveryStatus("pv1", "pv1", errors.New(strings.Repeat("x", 2*maxLastErrorSummaryLength)))
+	updatedPV, err := kubeClient.CoreV1().PersistentVolumes().Get(context.TODO(), "pv1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	status, err := GetDeliveryStatus(updatedPV)
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if status.Phase != PendingCreatePhase || status.Attempts != 2 {
+		t.Errorf("expected phase %s with 2 attempts but got %s with %d attempts", PendingCreatePhase, status.Phase, status.Attempts)
+	}
+	if len(status.LastError) != maxLastErrorSummaryLength {
+		t.Errorf("expected last error of length %d but got %d", maxLastErrorSummaryLength, len(status.LastError))
+	}
+	if status.NextRetryTime == nil || status.LastAttemptTime == nil || status.NextRetryTime.Before(status.LastAttemptTime) {
+		t.Errorf("expected next retry time to be after last attempt time but got %v", status)
+	}
+
+	// Volume which doesn't exist is ignored
+	pController.updateDeliveryStatus("pv2", "pv2", nil)
+}
+
+func TestUpdateDeliveryStatusSkipsUnchangedStatus(t *testing.T) {
+	deletionTime := metav1.Now()
+	tests := map[string]struct {
+		pvObj           *corev1.PersistentVolume
+		syncErr         error
+		isPatchExpected bool
+	}{
+		"when phase is changed": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv1",
+					Annotations: map[string]string{
+						"events.openebs.io/required":         "true",
+						"nfs.event.openebs.io/volume-create": "sent",
+						DeliveryStatusAnnotationKey:          `{"phase":"pending-create","attempts":1}`,
+					},
+				},
+			},
+			isPatchExpected: true,
+		},
+		"when phase, attempts and error are not changed": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv2",
+					Annotations: map[string]string{
+						"events.openebs.io/required": "true",
+						DeliveryStatusAnnotationKey:  `{"phase":"pending-create","attempts":0}`,
+					},
+				},
+			},
+		},
+		"when attempt is failed": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv3",
+					Annotations: map[string]string{
+						"events.openebs.io/required": "true",
+						DeliveryStatusAnnotationKey:  `{"phase":"pending-create","attempts":1,"lastError":"failed"}`,
+					},
+				},
+			},
+			syncErr:         errors.New("failed"),
+			isPatchExpected: true,
+		},
+		"when finalizers are released and volume is held by other finalizer": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv4",
+					DeletionTimestamp: &deletionTime,
+					Finalizers:        []string{"kubernetes.io/pv-protection"},
+					Annotations: map[string]string{
+						"events.openebs.io/required":         "true",
+						"nfs.event.openebs.io/volume-create": "sent",
+						"nfs.event.openebs.io/volume-delete": "sent",
+						DeliveryStatusAnnotationKey:          `{"phase":"finalizer-released","attempts":0}`,
+					},
+				},
+			},
+			syncErr: errors.New("failed"),
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(test.pvObj)
+			pController := &PVEventController{
+				controller:    newController("test", 1),
+				kubeClientset: kubeClient,
+			}
+			pController.updateDeliveryStatus(test.pvObj.Name, test.pvObj.Name, test.syncErr)
+
+			isPatched := false
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() == "patch" {
+					isPatched = true
+				}
+			}
+			if isPatched != test.isPatchExpected {
+				t.Errorf("%q test failed expected patch %t but got %t", name, test.isPatchExpected, isPatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// DrainSummary reports the outcome of draining volumes
+type DrainSummary struct {
+	// Drained holds the volumes whose pending events are
+	// delivered and event finalizers are removed
+	Drained []string
+	// Dropped holds the volumes whose event finalizers are removed
+	// forcefully along with the error of undelivered events
+	Dropped map[string]error
+	// Failed holds the volumes which couldn't be drained
+	Failed map[string]error
+}
+
+// IsComplete returns true if events of all the volumes are delivered
+// and event finalizers are removed
+func (s *DrainSummary) IsComplete() bool {
+	return len(s.Dropped) == 0 && len(s.Failed) == 0
+}
+
+// Drain delivers pending create and delete events of all the volumes
+// and then removes event finalizers from volumes and their dependent
+// resources. Informers of PV & PVC must be synced before draining.
+// NOTE: Volume events controller must not be running while draining
+// otherwise finalizers will be added back on live volumes
+func (pController *PVEventController) Drain(force bool) (*DrainSummary, error) {
+	pvList, err := pController.pvLister.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list PVs")
+	}
+	sort.Slice(pvList, func(i, j int) bool { return pvList[i].Name < pvList[j].Name })
+
+	summary := &DrainSummary{
+		Dropped: map[string]error{},
+		Failed:  map[string]error{},
+	}
+	for _, pvObj := range pvList {
+		if !isDrainRequired(pvObj) {
+			continue
+		}
+		err := pController.drainVolume(pvObj.Name)
+		if err == nil {
+			summary.Drained = append(summary.Drained, pvObj.Name)
+			continue
+		}
+		klog.Errorf("Failed to drain volume %s: %v", pvObj.Name, err)
+		if !force {
+			summary.Failed[pvObj.Name] = err
+			continue
+		}
+		releaseErr := pController.forceDrainVolume(pvObj.Name, err)
+		if releaseErr != nil {
+			summary.Failed[pvObj.Name] = releaseErr
+			continue
+		}
+		summary.Dropped[pvObj.Name] = err
+	}
+	return summary, nil
+}
+
+// drainVolume delivers pending events of the volume and
+// removes event finalizers on volume resources
+func (pController *PVEventController) drainVolume(name string) error {
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if shouldSendEvent(pvObj) {
+		eventSender, err := pController.getEventSender(pvObj)
+		if err != nil {
+			return err
+		}
+		err = pController.sendCreateEvent(eventSender, pvObj)
+		if err != nil {
+			return err
+		}
+		// Finalizers of volume under deletion are removed
+		// once delete event is sent
+		if pvObj.DeletionTimestamp != nil {
+			return pController.sendDeleteEvent(eventSender, pvObj)
+		}
+
+		// Volume is annotated with create event information
+		pvObj, err = pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !hasEventFinalizer(pvObj) {
+		return nil
+	}
+	eventSender, err := pController.getEventSender(pvObj)
+	if err != nil {
+		return err
+	}
+	err = eventSender.RemoveEventFinalizer()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove finalizers on volume %s", pvObj.Name)
+	}
+	return nil
+}
+
+// forceDrainVolume removes event finalizers of the volume
+// and records the events which are not delivered as dropped
+func (pController *PVEventController) forceDrainVolume(name string, drainErr error) error {
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return pController.releaseEventFinalizer(pvObj, "Volume is drained forcefully: "+drainErr.Error())
+}
+
+// isDrainRequired returns true if volume requires event to be
+// exported or volume resources are protected by event finalizer
+func isDrainRequired(pvObj *corev1.PersistentVolume) bool {
+	return pvObj.Annotations[annotationProcessEventKey] == eventRequiredAnnotationValue || hasEventFinalizer(pvObj)
+}
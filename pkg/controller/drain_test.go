@@ -0,0 +1,151 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestDrain(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	newPV := func(name string, annotations map[string]string, finalizers []string, isDeleted bool) *corev1.PersistentVolume {
+		pvObj := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: annotations,
+				Finalizers:  finalizers,
+				Labels:      map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+			},
+		}
+		if isDeleted {
+			pvObj.DeletionTimestamp = &deletionTimestamp
+		}
+		return pvObj
+	}
+	eventFinalizers := []string{"nfs.events.openebs.io/finalizer"}
+	pvObjs := []*corev1.PersistentVolume{
+		// Live volume whose create event is pending
+		newPV("pv1", map[string]string{"events.openebs.io/required": "true"}, eventFinalizers, false),
+		// Volume under deletion whose delete event is pending
+		newPV("pv2", map[string]string{
+			"events.openebs.io/required":         "true",
+			"nfs.event.openebs.io/volume-create": "sent",
+		}, eventFinalizers, true),
+		// Volume which doesn't require events
+		newPV("pv3", nil, nil, false),
+		// Volume whose events are sent but finalizer exist
+		newPV("pv4", map[string]string{
+			"events.openebs.io/required":         "true",
+			"nfs.event.openebs.io/volume-create": "sent",
+		}, eventFinalizers, false),
+	}
+	tests := map[string]struct {
+		force            bool
+		sendErr          error
+		expectedDrained  []string
+		expectedDropped  int
+		expectedFailed   int
+		expectedSent     int
+		expectedReleased int
+	}{
+		"when all the events are delivered": {
+			expectedDrained:  []string{"pv1", "pv2", "pv4"},
+			expectedSent:     2,
+			expectedReleased: 3,
+		},
+		"when events couldn't be delivered": {
+			sendErr:          errors.Errorf("server not reachable"),
+			expectedDrained:  []string{"pv4"},
+			expectedFailed:   2,
+			expectedReleased: 1,
+		},
+		"when events couldn't be delivered and drain is forced": {
+			force:            true,
+			sendErr:          errors.Errorf("server not reachable"),
+			expectedDrained:  []string{"pv4"},
+			expectedDropped:  2,
+			expectedReleased: 3,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			objs := make([]runtime.Object, 0, len(pvObjs))
+			for _, pvObj := range pvObjs {
+				if err := indexer.Add(pvObj); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+				objs = append(objs, pvObj)
+			}
+			var sent []collectorinterface.EventMetadata
+			var released int
+			pController := &PVEventController{
+				controller:    newController("test", 1),
+				kubeClientset: fake.NewSimpleClientset(objs...),
+				pvLister:      corev1listers.NewPersistentVolumeLister(indexer),
+				recorder:      &Recorder{EventRecorder: record.NewFakeRecorder(100)},
+				eventsSenderBuilder: func(collector collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+					return &fakeEventsSender{
+						VolumeEventCollector: collector,
+						sendErr:              test.sendErr,
+						sent:                 &sent,
+						released:             &released,
+					}
+				},
+			}
+
+			summary, err := pController.Drain(test.force)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if len(summary.Drained) != len(test.expectedDrained) {
+				t.Fatalf("%q test failed expected drained volumes %v but got %v", name, test.expectedDrained, summary.Drained)
+			}
+			for i := range summary.Drained {
+				if summary.Drained[i] != test.expectedDrained[i] {
+					t.Errorf("%q test failed expected drained volumes %v but got %v", name, test.expectedDrained, summary.Drained)
+				}
+			}
+			if len(summary.Dropped) != test.expectedDropped || len(summary.Failed) != test.expectedFailed {
+				t.Errorf("%q test failed expected %d dropped and %d failed volumes but got %v and %v",
+					name, test.expectedDropped, test.expectedFailed, summary.Dropped, summary.Failed)
+			}
+			if summary.IsComplete() != (test.expectedDropped == 0 && test.expectedFailed == 0) {
+				t.Errorf("%q test failed expected drain completion %t", name, !summary.IsComplete())
+			}
+			if len(sent) != test.expectedSent {
+				t.Errorf("%q test failed expected %d events to be sent but got %v", name, test.expectedSent, sent)
+			}
+			if released != test.expectedReleased {
+				t.Errorf("%q test failed expected %d volumes to be released but got %d", name, test.expectedReleased, released)
+			}
+		})
+	}
+}
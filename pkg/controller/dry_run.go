@@ -0,0 +1,96 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// renderedEvents holds the events rendered in dry run. Volumes are not
+// annotated in dry run, hence it prevents rendering events of a
+// volume on every reconcile. Events are evicted once volume is deleted
+type renderedEvents struct {
+	mutex  sync.Mutex
+	events map[string]struct{}
+}
+
+func newRenderedEvents() *renderedEvents {
+	return &renderedEvents{
+		events: map[string]struct{}{},
+	}
+}
+
+// isRendered returns true if event is already rendered
+func (r *renderedEvents) isRendered(metadata collectorinterface.EventMetadata) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, ok := r.events[renderedEventKey(metadata)]
+	return ok
+}
+
+// add marks the event as rendered
+func (r *renderedEvents) add(metadata collectorinterface.EventMetadata) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events[renderedEventKey(metadata)] = struct{}{}
+}
+
+// remove evicts the events of deleted volume
+func (r *renderedEvents) remove(pvUID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, eventType := range []collectorinterface.EventType{collectorinterface.CreateEventType, collectorinterface.DeleteEventType} {
+		delete(r.events, renderedEventKey(collectorinterface.EventMetadata{PVUID: pvUID, EventType: eventType}))
+	}
+}
+
+func renderedEventKey(metadata collectorinterface.EventMetadata) string {
+	return metadata.PVUID + "/" + string(metadata.EventType)
+}
+
+// isDryRun returns true if events are only rendered without sending
+// them or updating volumes
+func (pController *PVEventController) isDryRun() bool {
+	return pController.dryRunEvents != nil
+}
+
+// renderEvent collects the event and hands it over to the sender
+// only once. Volume is neither annotated nor delivery is recorded
+func (pController *PVEventController) renderEvent(
+	eventSender collectorinterface.EventsSender,
+	metadata collectorinterface.EventMetadata) error {
+	if pController.dryRunEvents.isRendered(metadata) {
+		return nil
+	}
+
+	data, err := collectEvent(eventSender, metadata.EventType)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %s event data of volume %s", metadata.EventType, metadata.PVName)
+	}
+
+	err = eventSender.Send(metadata, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render %s event data of volume %s", metadata.EventType, metadata.PVName)
+	}
+	pController.dryRunEvents.add(metadata)
+	klog.Infof("Rendered %s volume %s event in dry run", metadata.EventType, metadata.PVName)
+	return nil
+}
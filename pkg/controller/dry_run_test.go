@@ -0,0 +1,204 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// mutationCountingSender counts the calls which update volume resources
+type mutationCountingSender struct {
+	*fakeEventsSender
+	mutations *int
+}
+
+func (s *mutationCountingSender) AddEventFinalizer(pvObj *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	*s.mutations++
+	return pvObj, nil
+}
+
+func (s *mutationCountingSender) AnnotateCreateEvent(pvObj *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	*s.mutations++
+	return pvObj, nil
+}
+
+func (s *mutationCountingSender) AnnotateDeleteEvent(pvObj *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	*s.mutations++
+	return pvObj, nil
+}
+
+func (s *mutationCountingSender) RemoveEventFinalizer() error {
+	*s.mutations++
+	return nil
+}
+
+func TestDryRunSync(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	newPV := func(annotations map[string]string, isDeleted bool) *corev1.PersistentVolume {
+		pvObj := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pv1",
+				UID:         "uid1",
+				Annotations: annotations,
+				Finalizers:  []string{"nfs.events.openebs.io/finalizer"},
+				Labels:      map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+			},
+		}
+		if isDeleted {
+			pvObj.DeletionTimestamp = &deletionTimestamp
+		}
+		return pvObj
+	}
+	tests := map[string]struct {
+		pvObj              *corev1.PersistentVolume
+		sendErr            error
+		isErrExpected      bool
+		expectedEventTypes []collectorinterface.EventType
+	}{
+		"when create event of volume is not sent": {
+			pvObj:              newPV(map[string]string{"events.openebs.io/required": "true"}, false),
+			expectedEventTypes: []collectorinterface.EventType{collectorinterface.CreateEventType},
+		},
+		"when volume is deleted before sending create event": {
+			pvObj: newPV(map[string]string{"events.openebs.io/required": "true"}, true),
+			expectedEventTypes: []collectorinterface.EventType{
+				collectorinterface.CreateEventType,
+				collectorinterface.DeleteEventType,
+			},
+		},
+		"when volume is deleted after sending create event": {
+			pvObj: newPV(map[string]string{
+				"events.openebs.io/required":         "true",
+				"nfs.event.openebs.io/volume-create": "sent",
+			}, true),
+			expectedEventTypes: []collectorinterface.EventType{collectorinterface.DeleteEventType},
+		},
+		"when volume doesn't require events": {
+			pvObj: newPV(nil, false),
+		},
+		"when event couldn't be rendered": {
+			pvObj:         newPV(map[string]string{"events.openebs.io/required": "true"}, false),
+			sendErr:       errors.Errorf("invalid template"),
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var sent []collectorinterface.EventMetadata
+			var mutations int
+			pController := newFakeInspectController(t, []*corev1.PersistentVolume{test.pvObj}, test.sendErr, &sent, nil)
+			pController.dryRunEvents = newRenderedEvents()
+			pController.eventsSenderBuilder = func(collector collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+				return &mutationCountingSender{
+					fakeEventsSender: &fakeEventsSender{
+						VolumeEventCollector: collector,
+						sendErr:              test.sendErr,
+						sent:                 &sent,
+					},
+					mutations: &mutations,
+				}
+			}
+
+			// Volume is reconciled repeatedly since it is never annotated
+			for i := 0; i < 2; i++ {
+				err := pController.sync(test.pvObj)
+				if test.isErrExpected && err == nil {
+					t.Fatalf("%q test failed expected error to occur but got nil", name)
+				}
+				if !test.isErrExpected && err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+
+			var eventTypes []collectorinterface.EventType
+			for _, metadata := range sent {
+				eventTypes = append(eventTypes, metadata.EventType)
+			}
+			if !reflect.DeepEqual(eventTypes, test.expectedEventTypes) {
+				t.Errorf("%q test failed expected rendered events %v but got %v", name, test.expectedEventTypes, eventTypes)
+			}
+			if mutations != 0 {
+				t.Errorf("%q test failed expected volume not to be updated but got %d updates", name, mutations)
+			}
+		})
+	}
+}
+
+func TestDryRunEvictsEventsOfDeletedVolume(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	newPV := func(name, uid string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				UID:               types.UID(uid),
+				Annotations:       map[string]string{"events.openebs.io/required": "true"},
+				Labels:            map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				DeletionTimestamp: &deletionTimestamp,
+			},
+		}
+	}
+	tests := map[string]struct {
+		// deletedObj is the object received by delete handler
+		deletedObj interface{}
+	}{
+		"when deleted volume is received": {
+			deletedObj: newPV("pv1", "uid1"),
+		},
+		"when tombstone of deleted volume is received": {
+			deletedObj: cache.DeletedFinalStateUnknown{Key: "pv1", Obj: newPV("pv1", "uid1")},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			pvObjs := []*corev1.PersistentVolume{newPV("pv1", "uid1"), newPV("pv2", "uid2")}
+			var sent []collectorinterface.EventMetadata
+			pController := newFakeInspectController(t, pvObjs, nil, &sent, nil)
+			pController.dryRunEvents = newRenderedEvents()
+			for _, pvObj := range pvObjs {
+				if err := pController.sync(pvObj); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+			if len(pController.dryRunEvents.events) != 4 {
+				t.Fatalf("%q test failed expected 4 rendered events but got %v", name, pController.dryRunEvents.events)
+			}
+
+			pController.deletePV(test.deletedObj)
+			for _, eventType := range []collectorinterface.EventType{collectorinterface.CreateEventType, collectorinterface.DeleteEventType} {
+				if pController.dryRunEvents.isRendered(collectorinterface.EventMetadata{PVUID: "uid1", EventType: eventType}) {
+					t.Errorf("%q test failed expected %s event of deleted volume to be evicted", name, eventType)
+				}
+				if !pController.dryRunEvents.isRendered(collectorinterface.EventMetadata{PVUID: "uid2", EventType: eventType}) {
+					t.Errorf("%q test failed expected %s event of other volume to be retained", name, eventType)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// StuckFinalizerPolicy defines the action taken on volumes whose deletion
+// is blocked by event finalizer longer than the configured threshold
+type StuckFinalizerPolicy string
+
+const (
+	// StuckFinalizerPolicyNone disables the sweeper
+	StuckFinalizerPolicyNone StuckFinalizerPolicy = ""
+	// StuckFinalizerPolicyAlert logs an error for stuck volumes
+	StuckFinalizerPolicyAlert StuckFinalizerPolicy = "alert"
+	// StuckFinalizerPolicyWarn generates Warning event on stuck volumes
+	StuckFinalizerPolicyWarn StuckFinalizerPolicy = "warn"
+	// StuckFinalizerPolicyRelease removes event finalizer from resources of
+	// stuck volumes and records the events which are not yet delivered as dropped
+	StuckFinalizerPolicyRelease StuckFinalizerPolicy = "release"
+)
+
+// IsValid returns true if policy is supported by the sweeper
+func (p StuckFinalizerPolicy) IsValid() bool {
+	switch p {
+	case StuckFinalizerPolicyNone, StuckFinalizerPolicyAlert, StuckFinalizerPolicyWarn, StuckFinalizerPolicyRelease:
+		return true
+	}
+	return false
+}
+
+// sweepStuckFinalizers finds the volumes blocked by event finalizer longer
+// than threshold and takes the action based on configured policy
+func (pController *PVEventController) sweepStuckFinalizers() {
+	pvList, err := pController.pvLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list PVs to sweep stuck finalizers: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, pvObj := range pvList {
+		stuckDuration, isStuck := getStuckDuration(pvObj, now)
+		if !isStuck || stuckDuration < pController.stuckFinalizerThreshold {
+			continue
+		}
+		err := pController.handleStuckFinalizer(pvObj.DeepCopy(), stuckDuration)
+		if err != nil {
+			klog.Errorf("Failed to handle stuck finalizer of volume %s: %v", pvObj.Name, err)
+		}
+	}
+}
+
+// handleStuckFinalizer takes the action on stuck volume based on policy.
+// Events are generated irrespective of --generate-k8s-events since
+// policy is configured explicitly
+func (pController *PVEventController) handleStuckFinalizer(pvObj *corev1.PersistentVolume, stuckDuration time.Duration) error {
+	message := fmt.Sprintf("Volume deletion is blocked by event finalizer since %s", stuckDuration.Round(time.Second))
+	switch pController.stuckFinalizerPolicy {
+	case StuckFinalizerPolicyAlert:
+		klog.Errorf("PV %s: %s", pvObj.Name, message)
+	case StuckFinalizerPolicyWarn:
+		klog.Warningf("PV %s: %s", pvObj.Name, message)
+		pController.recorder.EventRecorder.Event(pvObj, corev1.EventTypeWarning, "StuckFinalizer", message)
+	case StuckFinalizerPolicyRelease:
+		return pController.releaseEventFinalizer(pvObj, message)
+	}
+	return nil
+}
+
+// releaseEventFinalizer removes the event finalizer from all resources
+// of the volume and records the events which are not yet delivered as dropped.
+// Sweeper runs along with workers on volumes fetched from cache, hence volume
+// is fetched again and skipped if worker has already sent the delete event
+// (worker removes finalizers in that case)
+func (pController *PVEventController) releaseEventFinalizer(pvObj *corev1.PersistentVolume, message string) error {
+	pvName := pvObj.Name
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), pvName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get volume %s", pvName)
+	}
+	if isDeleteVolumeEventSent(pvObj) || !hasEventFinalizer(pvObj) {
+		klog.V(4).Infof("Skipping release of event finalizers of PV %s since delete event is already sent", pvName)
+		return nil
+	}
+	eventSender, err := pController.getEventSender(pvObj)
+	if err != nil {
+		return err
+	}
+
+	var droppedEvents []collectorinterface.EventType
+	if !isCreateVolumeEventSent(pvObj) {
+		droppedEvents = append(droppedEvents, collectorinterface.CreateEventType)
+	}
+	if pvObj.DeletionTimestamp != nil && !isDeleteVolumeEventSent(pvObj) {
+		droppedEvents = append(droppedEvents, collectorinterface.DeleteEventType)
+	}
+	// Dropped events are recorded before releasing finalizers since
+	// deliveries are garbage collected once volume is deleted
+	for _, eventType := range droppedEvents {
+		pController.recordDroppedEvent(newEventMetadata(eventType, pvObj), message)
+	}
+
+	err = eventSender.RemoveEventFinalizer()
+	if err != nil {
+		return errors.Wrapf(err, "failed to release finalizers on volume %s", pvObj.Name)
+	}
+	klog.Warningf("Released event finalizers of PV %s: %s", pvObj.Name, message)
+	pController.recorder.EventRecorder.Event(pvObj, corev1.EventTypeWarning, "FinalizerReleased", "Released event finalizers forcefully. "+message)
+	for _, eventType := range droppedEvents {
+		pController.recorder.EventRecorder.Eventf(pvObj, corev1.EventTypeWarning, "VolumeEventDropped", "Dropped %s volume event", eventType)
+		klog.Warningf("Dropped %s event of PV %s", eventType, pvObj.Name)
+	}
+	return nil
+}
+
+// recordDroppedEvent records the event as dropped. Failure to
+// record is only logged since volume is already released
+func (pController *PVEventController) recordDroppedEvent(metadata collectorinterface.EventMetadata, reason string) {
+	if pController.deliveryTracker == nil {
+		return
+	}
+	err := pController.deliveryTracker.Drop(metadata, reason)
+	if err != nil {
+		klog.Errorf("Failed to record dropped %s event of volume %s: %v", metadata.EventType, metadata.PVName, err)
+	}
+}
+
+// getStuckDuration returns the time since volume is blocked by event
+// finalizer. It returns false if volume is not blocked by event finalizer
+func getStuckDuration(pvObj *corev1.PersistentVolume, now time.Time) (time.Duration, bool) {
+	if pvObj.DeletionTimestamp == nil || !hasEventFinalizer(pvObj) {
+		return 0, false
+	}
+	return now.Sub(pvObj.DeletionTimestamp.Time), true
+}
+
+// hasEventFinalizer returns true if volume has finalizer with
+// suffix(events.openebs.io/finalizer)
+func hasEventFinalizer(pvObj *corev1.PersistentVolume) bool {
+	for _, finalizer := range pvObj.Finalizers {
+		if strings.HasSuffix(finalizer, collectorinterface.VolumeEventsFinalizer) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,289 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/delivery"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+// fakeEventsSender records the events sent and counts
+// the finalizer removals of volumes
+type fakeEventsSender struct {
+	collectorinterface.VolumeEventCollector
+	sendErr            error
+	removeFinalizerErr error
+	sent               *[]collectorinterface.EventMetadata
+	released           *int
+	// finalized counts the calls to add event finalizers, it is optional
+	finalized *int
+}
+
+func (s *fakeEventsSender) Send(metadata collectorinterface.EventMetadata, data string) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	*s.sent = append(*s.sent, metadata)
+	return nil
+}
+
+func (s *fakeEventsSender) CollectCreateEvents() (string, error) {
+	return "{}", nil
+}
+
+func (s *fakeEventsSender) CollectDeleteEvents() (string, error) {
+	return "{}", nil
+}
+
+func (s *fakeEventsSender) AddEventFinalizer(pvObj *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	if s.finalized != nil {
+		*s.finalized++
+	}
+	return pvObj, nil
+}
+
+func (s *fakeEventsSender) AnnotateCreateEvent(pvObj *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	return pvObj, nil
+}
+
+func (s *fakeEventsSender) AnnotateDeleteEvent(pvObj *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	return pvObj, nil
+}
+
+func (s *fakeEventsSender) RemoveEventFinalizer() error {
+	if s.removeFinalizerErr != nil {
+		return s.removeFinalizerErr
+	}
+	*s.released++
+	return nil
+}
+
+func TestGetStuckDuration(t *testing.T) {
+	now := time.Now()
+	deletionTimestamp := metav1.NewTime(now.Add(-time.Hour))
+	tests := map[string]struct {
+		pvObj            *corev1.PersistentVolume
+		expectedDuration time.Duration
+		expectedIsStuck  bool
+	}{
+		"when volume is not marked for deletion": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "pv1",
+					Finalizers: []string{"nfs.events.openebs.io/finalizer"},
+				},
+			},
+		},
+		"when volume marked for deletion doesn't have event finalizer": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv2",
+					Finalizers:        []string{"kubernetes.io/pv-protection"},
+					DeletionTimestamp: &deletionTimestamp,
+				},
+			},
+		},
+		"when volume marked for deletion has event finalizer": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv3",
+					Finalizers:        []string{"kubernetes.io/pv-protection", "nfs.events.openebs.io/finalizer"},
+					DeletionTimestamp: &deletionTimestamp,
+				},
+			},
+			expectedDuration: time.Hour,
+			expectedIsStuck:  true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			duration, isStuck := getStuckDuration(test.pvObj, now)
+			if isStuck != test.expectedIsStuck {
+				t.Errorf("%q test failed expected stuck %t but got %t", name, test.expectedIsStuck, isStuck)
+			}
+			if duration.Round(time.Second) != test.expectedDuration {
+				t.Errorf("%q test failed expected duration %s but got %s", name, test.expectedDuration, duration)
+			}
+		})
+	}
+}
+
+func TestSweepStuckFinalizers(t *testing.T) {
+	newPV := func(name string, deletedBefore time.Duration, annotations map[string]string) *corev1.PersistentVolume {
+		deletionTimestamp := metav1.NewTime(time.Now().Add(-deletedBefore))
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Annotations:       annotations,
+				Finalizers:        []string{"nfs.events.openebs.io/finalizer"},
+				Labels:            map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				DeletionTimestamp: &deletionTimestamp,
+			},
+		}
+	}
+	tests := map[string]struct {
+		policy StuckFinalizerPolicy
+		pvObjs []*corev1.PersistentVolume
+		// apiPVObjs are the volumes in API server, pvObjs are used if it is nil
+		apiPVObjs []*corev1.PersistentVolume
+		// deliveredEvents of pv1 are recorded as sent before sweeping
+		deliveredEvents    []collectorinterface.EventType
+		removeFinalizerErr error
+		expectedReleased   int
+		expectedEvents     int
+		expectedPhases     map[collectorinterface.EventType]delivery.DeliveryPhase
+	}{
+		"when policy is alert": {
+			policy: StuckFinalizerPolicyAlert,
+			pvObjs: []*corev1.PersistentVolume{newPV("pv1", 2*time.Hour, nil)},
+		},
+		"when policy is warn": {
+			policy:         StuckFinalizerPolicyWarn,
+			pvObjs:         []*corev1.PersistentVolume{newPV("pv1", 2*time.Hour, nil), newPV("pv2", time.Minute, nil)},
+			expectedEvents: 1,
+		},
+		"when policy is release and no events are delivered": {
+			policy:           StuckFinalizerPolicyRelease,
+			pvObjs:           []*corev1.PersistentVolume{newPV("pv1", 2*time.Hour, nil)},
+			expectedReleased: 1,
+			// Released event and dropped create & delete events
+			expectedEvents: 3,
+			expectedPhases: map[collectorinterface.EventType]delivery.DeliveryPhase{
+				collectorinterface.CreateEventType: delivery.DeliveryPhaseDropped,
+				collectorinterface.DeleteEventType: delivery.DeliveryPhaseDropped,
+			},
+		},
+		"when delete event is sent by worker after volume is listed": {
+			policy: StuckFinalizerPolicyRelease,
+			pvObjs: []*corev1.PersistentVolume{newPV("pv1", 2*time.Hour, nil)},
+			apiPVObjs: []*corev1.PersistentVolume{
+				newPV("pv1", 2*time.Hour, map[string]string{
+					"nfs.event.openebs.io/volume-create": "sent",
+					"nfs.event.openebs.io/volume-delete": "sent",
+				}),
+			},
+			deliveredEvents: []collectorinterface.EventType{collectorinterface.CreateEventType, collectorinterface.DeleteEventType},
+			expectedPhases: map[collectorinterface.EventType]delivery.DeliveryPhase{
+				collectorinterface.CreateEventType: delivery.DeliveryPhaseSent,
+				collectorinterface.DeleteEventType: delivery.DeliveryPhaseSent,
+			},
+		},
+		"when volume is deleted after it is listed": {
+			policy:    StuckFinalizerPolicyRelease,
+			pvObjs:    []*corev1.PersistentVolume{newPV("pv1", 2*time.Hour, nil)},
+			apiPVObjs: []*corev1.PersistentVolume{},
+		},
+		"when policy is release and create event is delivered": {
+			policy: StuckFinalizerPolicyRelease,
+			pvObjs: []*corev1.PersistentVolume{
+				newPV("pv1", 2*time.Hour, map[string]string{"nfs.event.openebs.io/volume-create": "sent"}),
+				newPV("pv2", time.Minute, nil),
+			},
+			expectedReleased: 1,
+			expectedEvents:   2,
+		},
+		"when policy is release and finalizer removal fails": {
+			policy:             StuckFinalizerPolicyRelease,
+			pvObjs:             []*corev1.PersistentVolume{newPV("pv1", 2*time.Hour, nil)},
+			removeFinalizerErr: errors.Errorf("backing PVC not found"),
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, pvObj := range test.pvObjs {
+				if err := indexer.Add(pvObj); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+			apiPVObjs := test.apiPVObjs
+			if apiPVObjs == nil {
+				apiPVObjs = test.pvObjs
+			}
+			kubeClient := fake.NewSimpleClientset()
+			for _, pvObj := range apiPVObjs {
+				if _, err := kubeClient.CoreV1().PersistentVolumes().Create(context.TODO(), pvObj, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+			tracker := delivery.NewTracker(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+				runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{delivery.GroupVersionResource: delivery.Kind + "List"}), "kafka")
+			for _, eventType := range test.deliveredEvents {
+				metadata := newEventMetadata(eventType, test.pvObjs[0])
+				if err := tracker.Record(metadata, nil); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+			var released int
+			fakeRecorder := record.NewFakeRecorder(10)
+			pController := &PVEventController{
+				controller:      newController("test", 1),
+				kubeClientset:   kubeClient,
+				pvLister:        corev1listers.NewPersistentVolumeLister(indexer),
+				deliveryTracker: tracker,
+				// Sweeper events must be generated irrespective of generateEvents
+				recorder: &Recorder{EventRecorder: fakeRecorder},
+				eventsSenderBuilder: func(collector collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+					return &fakeEventsSender{
+						VolumeEventCollector: collector,
+						removeFinalizerErr:   test.removeFinalizerErr,
+						released:             &released,
+					}
+				},
+				stuckFinalizerPolicy:    test.policy,
+				stuckFinalizerThreshold: time.Hour,
+			}
+
+			pController.sweepStuckFinalizers()
+			if released != test.expectedReleased {
+				t.Errorf("%q test failed expected %d volumes to be released but got %d", name, test.expectedReleased, released)
+			}
+			if len(fakeRecorder.Events) != test.expectedEvents {
+				t.Errorf("%q test failed expected %d events but got %d", name, test.expectedEvents, len(fakeRecorder.Events))
+			}
+			for eventType, expectedPhase := range test.expectedPhases {
+				eventDelivery, err := tracker.Get("pv1", eventType)
+				if err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+				if eventDelivery == nil || eventDelivery.Status.Phase != expectedPhase {
+					t.Errorf("%q test failed expected %s event delivery in phase %s but got %v", name, eventType, expectedPhase, eventDelivery)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/delivery"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+)
+
+// VolumeStatus holds the delivery state of events of a volume
+type VolumeStatus struct {
+	PVName  string
+	PVUID   string
+	CASType string
+	// IsEventRequired states whether volume is opted-in to volume events
+	IsEventRequired bool
+	// Origin is synthetic/backfilled for backfilled volumes
+	Origin string
+	// Phase is derived from event annotations, deletion
+	// timestamp and finalizers of volume
+	Phase             DeliveryPhase
+	HasEventFinalizer bool
+	// DeliveryStatus is the status of last attempt, it is nil
+	// if volume events are not yet attempted
+	DeliveryStatus *DeliveryStatus
+	// Deliveries are VolumeEventDelivery resources of volume,
+	// it is empty if delivery tracking is disabled
+	Deliveries []delivery.VolumeEventDelivery
+}
+
+// IsPending returns true if volume is awaiting delivery of create
+// or delete event or removal of event finalizers
+func (s *VolumeStatus) IsPending() bool {
+	return s.IsEventRequired && (s.Phase == PendingCreatePhase || s.Phase == PendingDeletePhase)
+}
+
+// GetVolumeStatus returns the delivery state of events of given volume
+func (pController *PVEventController) GetVolumeStatus(pvName string) (*VolumeStatus, error) {
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), pvName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	status := newVolumeStatus(pvObj)
+	status.DeliveryStatus, err = GetDeliveryStatus(pvObj)
+	if err != nil {
+		return nil, err
+	}
+	if pController.deliveryTracker == nil {
+		return status, nil
+	}
+	for _, eventType := range []collectorinterface.EventType{collectorinterface.CreateEventType, collectorinterface.DeleteEventType} {
+		eventDelivery, err := pController.deliveryTracker.Get(pvName, eventType)
+		if err != nil {
+			return nil, err
+		}
+		if eventDelivery != nil {
+			status.Deliveries = append(status.Deliveries, *eventDelivery)
+		}
+	}
+	return status, nil
+}
+
+// ListPendingVolumes returns the status of volumes which are awaiting
+// delivery of create or delete event or removal of event finalizers
+func (pController *PVEventController) ListPendingVolumes() ([]*VolumeStatus, error) {
+	pvList, err := pController.pvLister.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list PVs")
+	}
+	sort.Slice(pvList, func(i, j int) bool { return pvList[i].Name < pvList[j].Name })
+
+	var pendingVolumes []*VolumeStatus
+	for _, pvObj := range pvList {
+		status := newVolumeStatus(pvObj)
+		if !status.IsPending() {
+			continue
+		}
+		status.DeliveryStatus, err = GetDeliveryStatus(pvObj)
+		if err != nil {
+			// Status will be overwritten by controller
+			klog.Warningf("%v", err)
+		}
+		pendingVolumes = append(pendingVolumes, status)
+	}
+	return pendingVolumes, nil
+}
+
+// Render returns the metadata and data of given event type collected
+// from the volume. Data is not transformed into configured payload
+func (pController *PVEventController) Render(pvName string, eventType collectorinterface.EventType) (collectorinterface.EventMetadata, string, error) {
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), pvName, metav1.GetOptions{})
+	if err != nil {
+		return collectorinterface.EventMetadata{}, "", err
+	}
+	eventSender, err := pController.getEventSender(pvObj)
+	if err != nil {
+		return collectorinterface.EventMetadata{}, "", err
+	}
+	data, err := collectEvent(eventSender, eventType)
+	if err != nil {
+		return collectorinterface.EventMetadata{}, "", errors.Wrapf(err, "failed to get %s event data of volume %s", eventType, pvName)
+	}
+	return newEventMetadata(eventType, pvObj), data, nil
+}
+
+// Resend delivers the given event of volume once again, irrespective
+// of recorded delivery. Event annotation is retained while sending so
+// that a failed resend doesn't make the volume look unsent, volume is
+// annotated once again after delivery. Event finalizers are removed
+// once delete event is delivered
+func (pController *PVEventController) Resend(pvName string, eventType collectorinterface.EventType) error {
+	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), pvName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if eventType == collectorinterface.DeleteEventType && pvObj.DeletionTimestamp == nil {
+		return errors.Errorf("delete event of volume %s can't be sent since volume is not yet deleted", pvName)
+	}
+
+	eventSender, err := pController.getEventSender(pvObj)
+	if err != nil {
+		return err
+	}
+	metadata := newEventMetadata(eventType, pvObj)
+	metadata.ResendID = string(uuid.NewUUID())
+	data, err := collectEvent(eventSender, eventType)
+	if err != nil {
+		pController.recordDelivery(metadata, err)
+		return errors.Wrapf(err, "failed to get %s event data of volume %s", eventType, pvName)
+	}
+	err = eventSender.Send(metadata, data)
+	pController.recordDelivery(metadata, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send %s event data of volume %s to server", eventType, pvName)
+	}
+
+	// Volume might be updated by controller while sending
+	pvObj, err = pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), pvName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get volume %s to annotate %s event information", pvName, eventType)
+	}
+	if eventType == collectorinterface.CreateEventType {
+		_, err = eventSender.AnnotateCreateEvent(pvObj)
+		return err
+	}
+	_, err = eventSender.AnnotateDeleteEvent(pvObj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to annotate volume %s with delete event information", pvName)
+	}
+	err = eventSender.RemoveEventFinalizer()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove finalizers on volume %s", pvName)
+	}
+	return nil
+}
+
+// collectEvent returns the data of given event type collected by collector
+func collectEvent(collector collectorinterface.VolumeEventCollector, eventType collectorinterface.EventType) (string, error) {
+	switch eventType {
+	case collectorinterface.CreateEventType:
+		return collector.CollectCreateEvents()
+	case collectorinterface.DeleteEventType:
+		return collector.CollectDeleteEvents()
+	}
+	return "", errors.Errorf("unsupported event type %q", eventType)
+}
+
+// newVolumeStatus returns the status of volume derived from volume object
+func newVolumeStatus(pvObj *corev1.PersistentVolume) *VolumeStatus {
+	return &VolumeStatus{
+		PVName:            pvObj.Name,
+		PVUID:             string(pvObj.UID),
+		CASType:           getCASType(pvObj),
+		IsEventRequired:   pvObj.Annotations[annotationProcessEventKey] == eventRequiredAnnotationValue,
+		Origin:            pvObj.Annotations[collectorinterface.VolumeEventOriginAnnotation],
+		Phase:             getDeliveryPhase(pvObj),
+		HasEventFinalizer: hasEventFinalizer(pvObj),
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+// newFakeInspectController returns the controller with given
+// volumes whose events are sent using fakeEventsSender
+func newFakeInspectController(t *testing.T,
+	pvObjs []*corev1.PersistentVolume,
+	sendErr error,
+	sent *[]collectorinterface.EventMetadata,
+	released *int) *PVEventController {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	objs := make([]runtime.Object, 0, len(pvObjs))
+	for _, pvObj := range pvObjs {
+		if err := indexer.Add(pvObj); err != nil {
+			t.Fatalf("expected error not to occur but got %v", err)
+		}
+		objs = append(objs, pvObj.DeepCopy())
+	}
+	return &PVEventController{
+		controller:    newController("test", 1),
+		kubeClientset: fake.NewSimpleClientset(objs...),
+		pvLister:      corev1listers.NewPersistentVolumeLister(indexer),
+		recorder:      &Recorder{EventRecorder: record.NewFakeRecorder(100)},
+		eventsSenderBuilder: func(collector collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+			return &fakeEventsSender{
+				VolumeEventCollector: collector,
+				sendErr:              sendErr,
+				sent:                 sent,
+				released:             released,
+			}
+		},
+	}
+}
+
+func TestListPendingVolumes(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	newPV := func(name string, annotations map[string]string, finalizers []string, isDeleted bool) *corev1.PersistentVolume {
+		pvObj := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: annotations,
+				Finalizers:  finalizers,
+				Labels:      map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+			},
+		}
+		if isDeleted {
+			pvObj.DeletionTimestamp = &deletionTimestamp
+		}
+		return pvObj
+	}
+	eventFinalizers := []string{"nfs.events.openebs.io/finalizer"}
+	pvObjs := []*corev1.PersistentVolume{
+		newPV("pv1", map[string]string{
+			"events.openebs.io/required":        "true",
+			"events.openebs.io/delivery-status": `{"phase":"pending-create","attempts":2,"lastError":"timeout"}`,
+		}, eventFinalizers, false),
+		newPV("pv2", map[string]string{
+			"events.openebs.io/required":         "true",
+			"nfs.event.openebs.io/volume-create": "sent",
+		}, eventFinalizers, false),
+		newPV("pv3", map[string]string{
+			"events.openebs.io/required":         "true",
+			"nfs.event.openebs.io/volume-create": "sent",
+		}, eventFinalizers, true),
+		newPV("pv4", map[string]string{
+			"events.openebs.io/required":         "true",
+			"nfs.event.openebs.io/volume-create": "sent",
+			"nfs.event.openebs.io/volume-delete": "sent",
+		}, eventFinalizers, true),
+		// Volume which doesn't require events
+		newPV("pv5", nil, nil, false),
+	}
+	pController := newFakeInspectController(t, pvObjs, nil, nil, nil)
+
+	pendingVolumes, err := pController.ListPendingVolumes()
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	expectedPhases := map[string]DeliveryPhase{
+		"pv1": PendingCreatePhase,
+		"pv3": PendingDeletePhase,
+		"pv4": PendingDeletePhase,
+	}
+	if len(pendingVolumes) != len(expectedPhases) {
+		t.Fatalf("expected %d pending volumes but got %d", len(expectedPhases), len(pendingVolumes))
+	}
+	for _, status := range pendingVolumes {
+		if expectedPhases[status.PVName] != status.Phase {
+			t.Errorf("expected volume %s in phase %q but got %q", status.PVName, expectedPhases[status.PVName], status.Phase)
+		}
+	}
+	if pendingVolumes[0].DeliveryStatus == nil || pendingVolumes[0].DeliveryStatus.Attempts != 2 {
+		t.Errorf("expected delivery status of pv1 with 2 attempts but got %v", pendingVolumes[0].DeliveryStatus)
+	}
+}
+
+func TestResend(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	tests := map[string]struct {
+		pvObj            *corev1.PersistentVolume
+		eventType        collectorinterface.EventType
+		sendErr          error
+		isErrExpected    bool
+		expectedRetained string
+		expectedReleased int
+		isSendExpected   bool
+	}{
+		"when create event is resent": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv1",
+					Annotations: map[string]string{
+						"events.openebs.io/required":         "true",
+						"nfs.event.openebs.io/volume-create": "sent",
+					},
+					Labels: map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				},
+			},
+			eventType:        collectorinterface.CreateEventType,
+			expectedRetained: "nfs.event.openebs.io/volume-create",
+			isSendExpected:   true,
+		},
+		"when delete event is resent": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv1",
+					Annotations: map[string]string{
+						"events.openebs.io/required":         "true",
+						"nfs.event.openebs.io/volume-create": "sent",
+						"nfs.event.openebs.io/volume-delete": "sent",
+					},
+					Finalizers:        []string{"nfs.events.openebs.io/finalizer"},
+					Labels:            map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+					DeletionTimestamp: &deletionTimestamp,
+				},
+			},
+			eventType:        collectorinterface.DeleteEventType,
+			expectedRetained: "nfs.event.openebs.io/volume-delete",
+			expectedReleased: 1,
+			isSendExpected:   true,
+		},
+		"when delete event of live volume is resent": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "pv1",
+					Labels: map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				},
+			},
+			eventType:     collectorinterface.DeleteEventType,
+			isErrExpected: true,
+		},
+		"when resent event couldn't be delivered": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv1",
+					Annotations: map[string]string{
+						"events.openebs.io/required":         "true",
+						"nfs.event.openebs.io/volume-create": "sent",
+					},
+					Labels: map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				},
+			},
+			eventType:        collectorinterface.CreateEventType,
+			sendErr:          errors.Errorf("server not reachable"),
+			isErrExpected:    true,
+			expectedRetained: "nfs.event.openebs.io/volume-create",
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var sent []collectorinterface.EventMetadata
+			var released int
+			pController := newFakeInspectController(t, []*corev1.PersistentVolume{test.pvObj}, test.sendErr, &sent, &released)
+
+			err := pController.Resend(test.pvObj.Name, test.eventType)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if test.isSendExpected && (len(sent) != 1 || sent[0].EventType != test.eventType) {
+				t.Errorf("%q test failed expected %s event to be sent but got %v", name, test.eventType, sent)
+			}
+			if released != test.expectedReleased {
+				t.Errorf("%q test failed expected %d finalizer removals but got %d", name, test.expectedReleased, released)
+			}
+			pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), test.pvObj.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			// fakeEventsSender doesn't annotate the volume, hence
+			// annotation exists only if it is retained while sending
+			if _, isExist := pvObj.Annotations[test.expectedRetained]; test.expectedRetained != "" && !isExist {
+				t.Errorf("%q test failed expected annotation %s to be retained but got %v", name, test.expectedRetained, pvObj.Annotations)
+			}
+			if test.isSendExpected && sent[0].ResendID == "" {
+				t.Errorf("%q test failed expected resend ID to be set but got %v", name, sent[0])
+			}
+		})
+	}
+}
@@ -22,6 +22,9 @@ import (
 	"strconv"
 	"time"
 
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/delivery"
+	"github.com/mayadata-io/volume-events-exporter/pkg/enrichment"
 	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	corev1informer "k8s.io/client-go/informers/core/v1"
@@ -54,6 +57,51 @@ type PVEventController struct {
 
 	// Recorder is an event recorder for recording Event resources to Kubernetes API.
 	recorder *Recorder
+
+	// eventsSenderBuilder builds the sender which pushes volume
+	// events to the configured sink
+	eventsSenderBuilder collectorinterface.EventsSenderBuilder
+
+	// resolvers are passed to collectors to enrich volume events
+	resolvers enrichment.Resolvers
+
+	// deliveryTracker records the delivery state of volume events,
+	// it is nil if tracking is disabled
+	deliveryTracker *delivery.Tracker
+
+	// stuckFinalizerPolicy is the action taken on volumes blocked by
+	// event finalizer longer than stuckFinalizerThreshold
+	stuckFinalizerPolicy    StuckFinalizerPolicy
+	stuckFinalizerThreshold time.Duration
+
+	// dryRunEvents holds the events rendered in dry run,
+	// it is nil if dry run is disabled
+	dryRunEvents *renderedEvents
+}
+
+// Options holds the optional configuration of PVEventController
+type Options struct {
+	// EventsSenderBuilder builds the sender of configured sink. Token
+	// based REST client is used if it is nil
+	EventsSenderBuilder collectorinterface.EventsSenderBuilder
+
+	// Resolvers enrich the volume events with additional context.
+	// Informers of the resolvers must be started along with PV informer
+	Resolvers enrichment.Resolvers
+
+	// DeliveryTracker records the delivery state of volume events in
+	// VolumeEventDelivery resources. Tracking is disabled if it is nil
+	DeliveryTracker *delivery.Tracker
+
+	// StuckFinalizerPolicy is the action taken periodically on volumes
+	// blocked by event finalizer longer than StuckFinalizerThreshold.
+	// Sweeper is disabled if it is StuckFinalizerPolicyNone
+	StuckFinalizerPolicy    StuckFinalizerPolicy
+	StuckFinalizerThreshold time.Duration
+
+	// DryRun enables rendering volume events to the configured sink
+	// without adding finalizers, annotating volumes or recording delivery
+	DryRun bool
 }
 
 // NewPVEventController will create new instantance of PVEventController
@@ -61,7 +109,8 @@ func NewPVEventController(kubeClientset kubernetes.Interface,
 	pvInformer corev1informer.PersistentVolumeInformer,
 	pvcInformer corev1informer.PersistentVolumeClaimInformer,
 	numWorker int,
-	generateEvents bool) Controller {
+	generateEvents bool,
+	options Options) Controller {
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(klog.Infof)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClientset.CoreV1().Events("")})
@@ -72,16 +121,32 @@ func NewPVEventController(kubeClientset kubernetes.Interface,
 	}
 
 	pvEventController := &PVEventController{
-		controller:    newController(volumeEventControllerName, numWorker),
-		kubeClientset: kubeClientset,
-		pvcLister:     pvcInformer.Lister(),
-		pvLister:      pvInformer.Lister(),
-		recorder:      recorder,
+		controller:          newController(volumeEventControllerName, numWorker),
+		kubeClientset:       kubeClientset,
+		pvcLister:           pvcInformer.Lister(),
+		pvLister:            pvInformer.Lister(),
+		recorder:            recorder,
+		eventsSenderBuilder: options.EventsSenderBuilder,
+		resolvers:           options.Resolvers,
+		deliveryTracker:     options.DeliveryTracker,
+
+		stuckFinalizerPolicy:    options.StuckFinalizerPolicy,
+		stuckFinalizerThreshold: options.StuckFinalizerThreshold,
+	}
+	if options.DryRun {
+		pvEventController.dryRunEvents = newRenderedEvents()
 	}
 	pvEventController.reconcile = pvEventController.processVolumeEvents
 	pvEventController.reconcilePeriod = GetSyncInterval()
+	if options.StuckFinalizerPolicy != StuckFinalizerPolicyNone {
+		// PVEventController.sync reconciles a volume, hence sweeper
+		// is set on sync hook of the embedded controller
+		pvEventController.controller.sync = pvEventController.sweepStuckFinalizers
+		pvEventController.controller.syncPeriod = GetSyncInterval()
+	}
 	pvEventController.cacheSyncWaiters = append(pvEventController.cacheSyncWaiters,
 		[]cache.InformerSynced{pvInformer.Informer().HasSynced, pvcInformer.Informer().HasSynced}...)
+	pvEventController.cacheSyncWaiters = append(pvEventController.cacheSyncWaiters, options.Resolvers.InformersSynced()...)
 
 	// Add event handlers
 	pvInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -108,16 +173,26 @@ func (pController *PVEventController) updatePV(oldObj, newObj interface{}) {
 		utilruntime.HandleError(fmt.Errorf("Couldn't get PV object %#v", newObj))
 		return
 	}
+	if oldPVObj, ok := oldObj.(*corev1.PersistentVolume); ok && isOnlyDeliveryStatusUpdated(oldPVObj, pvObj) {
+		return
+	}
 	klog.V(4).Infof("Queuing PV %s for update event", pvObj.Name)
 	pController.enqueue(pvObj)
 }
 
 func (pController *PVEventController) deletePV(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
 	pvObj, ok := obj.(*corev1.PersistentVolume)
 	if !ok {
 		utilruntime.HandleError(fmt.Errorf("Couldn't get PV object %#v", obj))
 		return
 	}
+	if pController.isDryRun() {
+		// Events of deleted volume will never be rendered again
+		pController.dryRunEvents.remove(string(pvObj.UID))
+	}
 	klog.V(4).Infof("Queuing PV %s for delete event", pvObj.Name)
 	pController.enqueue(pvObj)
 }
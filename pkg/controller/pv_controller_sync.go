@@ -34,7 +34,7 @@ import (
 )
 
 const (
-	annotationProcessEventKey    = "events.openebs.io/required"
+	annotationProcessEventKey    = collectorinterface.VolumeEventsRequiredAnnotation
 	eventRequiredAnnotationValue = "true"
 )
 
@@ -55,16 +55,20 @@ func (pController *PVEventController) processVolumeEvents(key string) (bool, err
 	pvObj, err := pController.kubeClientset.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
 	if k8serror.IsNotFound(err) {
 		runtime.HandleError(fmt.Errorf("PV %q has been deleted", key))
-		return false, nil
+		return false, pController.deleteDeliveries(name)
 	}
 	if err != nil {
 		return false, err
 	}
 
+	isEventRequired := shouldSendEvent(pvObj)
 	err = pController.sync(pvObj)
 	if err != nil {
 		pController.recorder.Event(pvObj, corev1.EventTypeWarning, "EventInformation", err.Error())
 	}
+	if isEventRequired && !pController.isDryRun() {
+		pController.updateDeliveryStatus(key, pvObj.Name, err)
+	}
 
 	// Something went wrong let's retry after sometime
 	return true, err
@@ -74,7 +78,7 @@ func (pController *PVEventController) processVolumeEvents(key string) (bool, err
 // NOTE: It will ensure to send event information only once
 func (pController *PVEventController) sync(pvObj *corev1.PersistentVolume) error {
 	klog.V(4).Infof("Reconciling PV %s to send volume events", pvObj.Name)
-	if !shouldSendEvent(pvObj) {
+	if !shouldSendEvent(pvObj) && !pController.isEventFinalizerMissing(pvObj) {
 		// If no action is required then return from here
 		return nil
 	}
@@ -85,6 +89,18 @@ func (pController *PVEventController) sync(pvObj *corev1.PersistentVolume) error
 		return err
 	}
 
+	// Add event finalizers before sending create event so that
+	// volume can't be deleted without sending delete event. They
+	// are added back on live volumes if they are missing even
+	// after create event is sent
+	if pvObj.DeletionTimestamp == nil && !pController.isDryRun() {
+		newPVObj, err := eventSender.AddEventFinalizer(pvObj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add finalizers on volume %s", pvObj.Name)
+		}
+		pvObj = newPVObj
+	}
+
 	// Send create event information
 	err = pController.sendCreateEvent(eventSender, pvObj)
 	if err != nil {
@@ -108,16 +124,30 @@ func (pController *PVEventController) sendCreateEvent(
 
 	// Send create information
 	if !isCreateVolumeEventSent(pvObj) {
-		// Get create event related data
-		data, err := eventSender.CollectCreateEvents()
+		metadata := newEventMetadata(collectorinterface.CreateEventType, pvObj)
+		if pController.isDryRun() {
+			return pController.renderEvent(eventSender, metadata)
+		}
+		// Event might have been delivered but annotating volume was failed
+		isDelivered, err := pController.isEventDelivered(metadata)
 		if err != nil {
-			return errors.Wrapf(err, "failed to get create event data of volume %s", pvObj.Name)
+			return err
 		}
 
-		// Send create event data
-		err = eventSender.Send(data)
-		if err != nil {
-			return errors.Wrapf(err, "failed to send create event data of volume %s to server", pvObj.Name)
+		if !isDelivered {
+			// Get create event related data
+			data, err := eventSender.CollectCreateEvents()
+			if err != nil {
+				pController.recordDelivery(metadata, err)
+				return errors.Wrapf(err, "failed to get create event data of volume %s", pvObj.Name)
+			}
+
+			// Send create event data
+			err = eventSender.Send(metadata, data)
+			pController.recordDelivery(metadata, err)
+			if err != nil {
+				return errors.Wrapf(err, "failed to send create event data of volume %s to server", pvObj.Name)
+			}
 		}
 
 		_, err = eventSender.AnnotateCreateEvent(pvObj)
@@ -140,16 +170,30 @@ func (pController *PVEventController) sendDeleteEvent(
 	pvObj *corev1.PersistentVolume) error {
 	if pvObj.DeletionTimestamp != nil {
 		if !isDeleteVolumeEventSent(pvObj) {
-			// Get delete event related data
-			data, err := eventSender.CollectDeleteEvents()
+			metadata := newEventMetadata(collectorinterface.DeleteEventType, pvObj)
+			if pController.isDryRun() {
+				return pController.renderEvent(eventSender, metadata)
+			}
+			// Event might have been delivered but annotating volume was failed
+			isDelivered, err := pController.isEventDelivered(metadata)
 			if err != nil {
-				return errors.Wrapf(err, "failed to get delete event data of volume %s", pvObj.Name)
+				return err
 			}
 
-			// Send delete event data
-			err = eventSender.Send(data)
-			if err != nil {
-				return errors.Wrapf(err, "failed to send delete event data of volume %s to server", pvObj.Name)
+			if !isDelivered {
+				// Get delete event related data
+				data, err := eventSender.CollectDeleteEvents()
+				if err != nil {
+					pController.recordDelivery(metadata, err)
+					return errors.Wrapf(err, "failed to get delete event data of volume %s", pvObj.Name)
+				}
+
+				// Send delete event data
+				err = eventSender.Send(metadata, data)
+				pController.recordDelivery(metadata, err)
+				if err != nil {
+					return errors.Wrapf(err, "failed to send delete event data of volume %s to server", pvObj.Name)
+				}
 			}
 
 			// Annotate resource saying delete event is sent to REST server
@@ -161,6 +205,9 @@ func (pController *PVEventController) sendDeleteEvent(
 			klog.Infof("Successfully sent delete volume %s event to server", pvObj.Name)
 		}
 
+		if pController.isDryRun() {
+			return nil
+		}
 		err := eventSender.RemoveEventFinalizer()
 		if err != nil {
 			return errors.Wrapf(err, "failed to remove finalizers on volume %s", pvObj.Name)
@@ -169,33 +216,107 @@ func (pController *PVEventController) sendDeleteEvent(
 	return nil
 }
 
+// isEventDelivered returns true if delivery tracker has recorded the
+// event as delivered. It always returns false if tracking is disabled
+func (pController *PVEventController) isEventDelivered(metadata collectorinterface.EventMetadata) (bool, error) {
+	if pController.deliveryTracker == nil {
+		return false, nil
+	}
+	isDelivered, err := pController.deliveryTracker.IsDelivered(metadata)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get %s event delivery of volume %s", metadata.EventType, metadata.PVName)
+	}
+	return isDelivered, nil
+}
+
+// recordDelivery records the attempt to deliver the event. Failure to
+// record is only logged since annotations also hold the delivery state
+func (pController *PVEventController) recordDelivery(metadata collectorinterface.EventMetadata, deliveryErr error) {
+	if pController.deliveryTracker == nil {
+		return
+	}
+	err := pController.deliveryTracker.Record(metadata, deliveryErr)
+	if err != nil {
+		klog.Errorf("Failed to record %s event delivery of volume %s: %v", metadata.EventType, metadata.PVName, err)
+	}
+}
+
+// deleteDeliveries deletes the delivery records of deleted volume. Volume
+// is deleted only after its delete event is either sent or dropped
+func (pController *PVEventController) deleteDeliveries(pvName string) error {
+	if pController.deliveryTracker == nil {
+		return nil
+	}
+	err := pController.deliveryTracker.Delete(pvName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete event deliveries of volume %s", pvName)
+	}
+	return nil
+}
+
 // getEventSender will return event sender which implements all the methods of event sender interface
 func (pController *PVEventController) getEventSender(pvObj *corev1.PersistentVolume) (collectorinterface.EventsSender, error) {
 	// Add more types based on underlying volume type
-	casType, isCASTypeExist := pvObj.Labels[OpenEBSCASLabelKey]
-	if !isCASTypeExist {
-		// If volume is provisioned via CSI
-		if pvObj.Spec.CSI != nil {
-			casType = pvObj.Spec.CSI.VolumeAttributes[OpenEBSCASLabelKey]
-		}
-	}
-
+	casType := getCASType(pvObj)
 	if casType == "" {
 		return nil, errors.Errorf("CAS type is not found on volume %s", pvObj.Name)
 	}
 	switch casType {
 	case nfspv.OpenEBSNFSCASLabelValue:
-		return tokenauth.NewTokenClient(
+		return pController.newEventsSender(
 			nfspv.NewNFSVolume(
 				pController.kubeClientset,
 				pController.pvcLister,
 				pController.pvLister,
 				pvObj,
-				collectorinterface.JSONDataType)), nil
+				collectorinterface.JSONDataType,
+				pController.resolvers)), nil
 	}
 	return nil, errors.Errorf("event sender is not available for volume %s of CAS type %s", pvObj.Name, casType)
 }
 
+// newEventsSender returns sender of configured sink for given
+// collector. Token based REST client is used if no sink is configured
+func (pController *PVEventController) newEventsSender(
+	collector collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+	if pController.eventsSenderBuilder == nil {
+		return tokenauth.NewTokenClient(collector)
+	}
+	return pController.eventsSenderBuilder(collector)
+}
+
+// getCASType returns the CAS type of the volume from labels
+// or from CSI volume attributes
+func getCASType(pvObj *corev1.PersistentVolume) string {
+	casType, isCASTypeExist := pvObj.Labels[OpenEBSCASLabelKey]
+	if !isCASTypeExist {
+		// If volume is provisioned via CSI
+		if pvObj.Spec.CSI != nil {
+			casType = pvObj.Spec.CSI.VolumeAttributes[OpenEBSCASLabelKey]
+		}
+	}
+	return casType
+}
+
+// newEventMetadata returns metadata of the given event type for the volume
+func newEventMetadata(eventType collectorinterface.EventType, pvObj *corev1.PersistentVolume) collectorinterface.EventMetadata {
+	metadata := collectorinterface.EventMetadata{
+		EventType: eventType,
+		PVName:    pvObj.Name,
+		PVUID:     string(pvObj.UID),
+		CASType:   getCASType(pvObj),
+		Timestamp: pvObj.CreationTimestamp.Time,
+	}
+	// Only create event is synthetic for backfilled volumes
+	if eventType == collectorinterface.CreateEventType {
+		metadata.Origin = pvObj.Annotations[collectorinterface.VolumeEventOriginAnnotation]
+	}
+	if eventType == collectorinterface.DeleteEventType && pvObj.DeletionTimestamp != nil {
+		metadata.Timestamp = pvObj.DeletionTimestamp.Time
+	}
+	return metadata
+}
+
 // shouldSendEvent will return true based on following conditions:
 // 1. Retrun true if volume requires event to be exported(based on annotation "events.openebs.io/required": "true")
 // 2. Retrun true if deletion timestamp is set and create event is not yet send
@@ -212,6 +333,15 @@ func shouldSendEvent(pvObj *corev1.PersistentVolume) bool {
 	return pvObj.DeletionTimestamp != nil
 }
 
+// isEventFinalizerMissing returns true if volume requires event to be
+// exported and live volume is not protected by event finalizer
+func (pController *PVEventController) isEventFinalizerMissing(pvObj *corev1.PersistentVolume) bool {
+	if pvObj.Annotations[annotationProcessEventKey] != eventRequiredAnnotationValue {
+		return false
+	}
+	return pvObj.DeletionTimestamp == nil && !hasEventFinalizer(pvObj) && !pController.isDryRun()
+}
+
 // isCreateVolumeEventSent will return true if volume has
 // suffix(event.openebs.io/volume-create) in annotations and value is sent
 func isCreateVolumeEventSent(pvObj *corev1.PersistentVolume) bool {
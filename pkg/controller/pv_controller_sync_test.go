@@ -19,9 +19,15 @@ package controller
 import (
 	"testing"
 
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/delivery"
 	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestShouldSendEvent(t *testing.T) {
@@ -367,3 +373,132 @@ func TestGetEventSender(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessVolumeEventsOfDeletedVolume(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{delivery.GroupVersionResource: delivery.Kind + "List"})
+	tracker := delivery.NewTracker(dynamicClient, "kafka")
+	for _, pvName := range []string{"pv1", "pv2"} {
+		for _, eventType := range []collectorinterface.EventType{collectorinterface.CreateEventType, collectorinterface.DeleteEventType} {
+			metadata := collectorinterface.EventMetadata{EventType: eventType, PVName: pvName, PVUID: "uid-" + pvName}
+			if err := tracker.Record(metadata, nil); err != nil {
+				t.Fatalf("expected error not to occur while recording delivery but got %v", err)
+			}
+		}
+	}
+	pController := &PVEventController{
+		controller:      newController("test", 1),
+		kubeClientset:   fake.NewSimpleClientset(),
+		deliveryTracker: tracker,
+	}
+
+	shouldRequeue, err := pController.processVolumeEvents("pv1")
+	if err != nil || shouldRequeue {
+		t.Fatalf("expected deleted volume not to be requeued but got requeue %t error %v", shouldRequeue, err)
+	}
+	deliveries, err := tracker.List()
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("expected deliveries of only pv2 but got %v", deliveries)
+	}
+	for _, eventDelivery := range deliveries {
+		if eventDelivery.Spec.PVName != "pv2" {
+			t.Errorf("expected deliveries of only pv2 but got delivery %s of %s", eventDelivery.Name, eventDelivery.Spec.PVName)
+		}
+	}
+}
+
+func TestSyncAddsMissingEventFinalizer(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	tests := map[string]struct {
+		pvObj             *corev1.PersistentVolume
+		expectedFinalized int
+	}{
+		"when create event is not yet sent": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pv1",
+					Annotations: map[string]string{"events.openebs.io/required": "true"},
+					Labels:      map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				},
+			},
+			expectedFinalized: 1,
+		},
+		"when create event is sent and finalizer is missing": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv2",
+					Annotations: map[string]string{
+						"events.openebs.io/required":         "true",
+						"nfs.event.openebs.io/volume-create": "sent",
+					},
+					Labels: map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				},
+			},
+			expectedFinalized: 1,
+		},
+		"when create event is sent and finalizer exists": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv3",
+					Annotations: map[string]string{
+						"events.openebs.io/required":         "true",
+						"nfs.event.openebs.io/volume-create": "sent",
+					},
+					Finalizers: []string{"nfs.events.openebs.io/finalizer"},
+					Labels:     map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				},
+			},
+		},
+		"when volume is not opted-in": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "pv4",
+					Labels: map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				},
+			},
+		},
+		"when volume is under deletion": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv5",
+					Annotations: map[string]string{
+						"events.openebs.io/required":         "true",
+						"nfs.event.openebs.io/volume-create": "sent",
+						"nfs.event.openebs.io/volume-delete": "sent",
+					},
+					DeletionTimestamp: &deletionTimestamp,
+					Labels:            map[string]string{OpenEBSCASLabelKey: nfspv.OpenEBSNFSCASLabelValue},
+				},
+			},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var sent []collectorinterface.EventMetadata
+			var released, finalized int
+			pController := newFakeInspectController(t, []*corev1.PersistentVolume{test.pvObj}, nil, &sent, &released)
+			pController.eventsSenderBuilder = func(collector collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+				return &fakeEventsSender{
+					VolumeEventCollector: collector,
+					sent:                 &sent,
+					released:             &released,
+					finalized:            &finalized,
+				}
+			}
+
+			err := pController.sync(test.pvObj)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if finalized != test.expectedFinalized {
+				t.Errorf("%q test failed expected finalizers to be added %d times but got %d", name, test.expectedFinalized, finalized)
+			}
+		})
+	}
+}
@@ -16,10 +16,41 @@ limitations under the License.
 
 package controller
 
-import "context"
+import (
+	"context"
+
+	collectorinterface "github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+)
 
 // Controller defines interface to execute controller
 type Controller interface {
 	// Run method to run controller
 	Run(ctx context.Context) error
 }
+
+// Drainer defines interface to deliver pending volume events and
+// remove event finalizers from all the volumes
+type Drainer interface {
+	// Drain delivers pending events and removes event finalizers. If force
+	// is true then finalizers are removed even if events are not delivered
+	Drain(force bool) (*DrainSummary, error)
+}
+
+// Backfiller defines interface to send create events of the volumes
+// which were provisioned before volume events are enabled
+type Backfiller interface {
+	// Backfill sends create events of the volumes matching given options
+	Backfill(options BackfillOptions) (*BackfillSummary, error)
+}
+
+// Inspector defines interface to inspect and replay events of volumes
+type Inspector interface {
+	// GetVolumeStatus returns the delivery state of events of the volume
+	GetVolumeStatus(pvName string) (*VolumeStatus, error)
+	// ListPendingVolumes returns the volumes awaiting delivery of events
+	ListPendingVolumes() ([]*VolumeStatus, error)
+	// Render returns the data collected for given event of the volume
+	Render(pvName string, eventType collectorinterface.EventType) (collectorinterface.EventMetadata, string, error)
+	// Resend clears the event annotation and delivers the event once again
+	Resend(pvName string, eventType collectorinterface.EventType) error
+}
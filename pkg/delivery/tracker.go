@@ -0,0 +1,313 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// maxLastErrorLength is the maximum length of error recorded in status
+	maxLastErrorLength = 1024
+	// maxAcknowledgements is the maximum number of latest acknowledgements
+	// recorded in status
+	maxAcknowledgements = 10
+
+	// maxNameLength & maxLabelValueLength are the maximum length
+	// of object name and label value allowed by Kubernetes
+	maxNameLength       = 253
+	maxLabelValueLength = 63
+	// hashLength is the length of hash suffixed to shortened values
+	hashLength = 10
+)
+
+// Tracker records the delivery state of volume events
+// in VolumeEventDelivery resources
+type Tracker struct {
+	client dynamic.NamespaceableResourceInterface
+
+	// destination is recorded in acknowledgements
+	destination string
+
+	now func() time.Time
+}
+
+// NewTracker returns the tracker which records acknowledgements
+// of given destination
+func NewTracker(dynamicClient dynamic.Interface, destination string) *Tracker {
+	return &Tracker{
+		client:      dynamicClient.Resource(GroupVersionResource),
+		destination: destination,
+		now:         time.Now,
+	}
+}
+
+// GetName returns the name of VolumeEventDelivery of given volume & event
+// type. Volume name is shortened if name exceeds the allowed length
+func GetName(pvName string, eventType collectorinterface.EventType) string {
+	suffix := "-" + string(eventType)
+	return shorten(pvName, maxNameLength-len(suffix)) + suffix
+}
+
+// Get returns the VolumeEventDelivery of given volume & event type. It
+// returns nil if delivery is not yet recorded
+func (t *Tracker) Get(pvName string, eventType collectorinterface.EventType) (*VolumeEventDelivery, error) {
+	name := GetName(pvName, eventType)
+	obj, err := t.client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get %s %s", Kind, name)
+	}
+	return fromUnstructured(obj)
+}
+
+// List returns all the VolumeEventDelivery resources
+func (t *Tracker) List() ([]VolumeEventDelivery, error) {
+	objList, err := t.client.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s resources", Kind)
+	}
+	deliveries := make([]VolumeEventDelivery, 0, len(objList.Items))
+	for i := range objList.Items {
+		delivery, err := fromUnstructured(&objList.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, nil
+}
+
+// IsDelivered returns true if event of the volume is already
+// accepted by the destination
+func (t *Tracker) IsDelivered(metadata collectorinterface.EventMetadata) (bool, error) {
+	delivery, err := t.Get(metadata.PVName, metadata.EventType)
+	if err != nil {
+		return false, err
+	}
+	return delivery != nil &&
+		delivery.Spec.PVUID == metadata.PVUID &&
+		delivery.Status.Phase == DeliveryPhaseSent, nil
+}
+
+// Record records an attempt to deliver the event. Attempt is
+// considered as failed if deliveryErr is not nil. Delivery is
+// fetched again on conflict so that concurrent attempts are not lost
+func (t *Tracker) Record(metadata collectorinterface.EventMetadata, deliveryErr error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		delivery, err := t.getOrCreate(metadata)
+		if err != nil {
+			return err
+		}
+
+		now := metav1.NewTime(t.now())
+		status := &delivery.Status
+		status.Attempts++
+		status.LastAttemptTime = &now
+		if status.FirstAttemptTime == nil {
+			status.FirstAttemptTime = &now
+		}
+		if deliveryErr != nil {
+			status.Phase = DeliveryPhaseFailed
+			status.LastError = truncate(deliveryErr.Error(), maxLastErrorLength)
+		} else {
+			status.Phase = DeliveryPhaseSent
+			status.LastError = ""
+			status.SentTime = &now
+			status.Acknowledgements = append(status.Acknowledgements, Acknowledgement{
+				Destination:    t.destination,
+				AcknowledgedAt: now,
+			})
+			if len(status.Acknowledgements) > maxAcknowledgements {
+				status.Acknowledgements = status.Acknowledgements[len(status.Acknowledgements)-maxAcknowledgements:]
+			}
+		}
+		return t.updateStatus(delivery)
+	})
+}
+
+// Drop records that the event will never be delivered since finalizers
+// of the volume are released without delivering it. Already delivered
+// events are left as it is, delivery is fetched again on conflict so
+// that event delivered concurrently is not overwritten
+func (t *Tracker) Drop(metadata collectorinterface.EventMetadata, reason string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		delivery, err := t.getOrCreate(metadata)
+		if err != nil {
+			return err
+		}
+		if delivery.Status.Phase == DeliveryPhaseSent {
+			return nil
+		}
+		delivery.Status.Phase = DeliveryPhaseDropped
+		delivery.Status.LastError = truncate(reason, maxLastErrorLength)
+		return t.updateStatus(delivery)
+	})
+}
+
+// Delete deletes the VolumeEventDelivery resources of given volume. It
+// is invoked once volume is deleted i.e after delete event is either
+// sent or dropped, so that resources are not accumulated over time
+func (t *Tracker) Delete(pvName string) error {
+	for _, eventType := range []collectorinterface.EventType{collectorinterface.CreateEventType, collectorinterface.DeleteEventType} {
+		name := GetName(pvName, eventType)
+		err := t.client.Delete(context.TODO(), name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete %s %s", Kind, name)
+		}
+	}
+	return nil
+}
+
+// getOrCreate returns the VolumeEventDelivery of given event, it will be
+// created if it doesn't exist or belongs to an earlier volume with same name
+func (t *Tracker) getOrCreate(metadata collectorinterface.EventMetadata) (*VolumeEventDelivery, error) {
+	delivery, err := t.Get(metadata.PVName, metadata.EventType)
+	if err != nil {
+		return nil, err
+	}
+	if delivery != nil && delivery.Spec.PVUID != metadata.PVUID {
+		// Delivery belongs to an earlier volume with same name
+		err = t.client.Delete(context.TODO(), delivery.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to delete stale %s %s", Kind, delivery.Name)
+		}
+		delivery = nil
+	}
+	if delivery == nil {
+		return t.create(metadata)
+	}
+	return delivery, nil
+}
+
+// create creates the VolumeEventDelivery of given event. Volume is set as
+// controller owner so that delivery is garbage collected along with volume
+func (t *Tracker) create(metadata collectorinterface.EventMetadata) (*VolumeEventDelivery, error) {
+	delivery := &VolumeEventDelivery{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupName + "/" + Version,
+			Kind:       Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: GetName(metadata.PVName, metadata.EventType),
+			Labels: map[string]string{
+				PVNameLabelKey:    shorten(metadata.PVName, maxLabelValueLength),
+				EventTypeLabelKey: string(metadata.EventType),
+			},
+		},
+		Spec: VolumeEventDeliverySpec{
+			PVName:    metadata.PVName,
+			PVUID:     metadata.PVUID,
+			EventType: string(metadata.EventType),
+			CASType:   metadata.CASType,
+		},
+		Status: VolumeEventDeliveryStatus{
+			Phase: DeliveryPhasePending,
+		},
+	}
+	if metadata.PVUID != "" {
+		isController := true
+		delivery.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: "v1",
+				Kind:       "PersistentVolume",
+				Name:       metadata.PVName,
+				UID:        types.UID(metadata.PVUID),
+				Controller: &isController,
+			},
+		}
+	}
+	obj, err := toUnstructured(delivery)
+	if err != nil {
+		return nil, err
+	}
+	obj, err = t.client.Create(context.TODO(), obj, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s %s", Kind, delivery.Name)
+	}
+	createdDelivery, err := fromUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	// Status is ignored during creation when status subresource is enabled
+	createdDelivery.Status = delivery.Status
+	return createdDelivery, nil
+}
+
+func (t *Tracker) updateStatus(delivery *VolumeEventDelivery) error {
+	obj, err := toUnstructured(delivery)
+	if err != nil {
+		return err
+	}
+	_, err = t.client.UpdateStatus(context.TODO(), obj, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update status of %s %s", Kind, delivery.Name)
+	}
+	return nil
+}
+
+func toUnstructured(delivery *VolumeEventDelivery) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(delivery)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert %s %s", Kind, delivery.Name)
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+func fromUnstructured(obj *unstructured.Unstructured) (*VolumeEventDelivery, error) {
+	delivery := &VolumeEventDelivery{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), delivery)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert %s %s", Kind, obj.GetName())
+	}
+	return delivery, nil
+}
+
+// shorten returns the value as it is if it doesn't exceed the given length.
+// Otherwise value is truncated and suffixed with its hash so that shortened
+// values of different volumes don't collide
+func shorten(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	hash := sha256.Sum256([]byte(value))
+	prefix := strings.TrimRight(value[:maxLength-hashLength-1], "-.")
+	return prefix + "-" + hex.EncodeToString(hash[:])[:hashLength]
+}
+
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength]
+}
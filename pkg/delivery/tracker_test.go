@@ -0,0 +1,425 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newFakeTracker(now time.Time) *Tracker {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{GroupVersionResource: Kind + "List"})
+	tracker := NewTracker(dynamicClient, "kafka")
+	tracker.now = func() time.Time { return now }
+	return tracker
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2021, time.October, 1, 10, 0, 0, 0, time.UTC)
+	metadata := collectorinterface.EventMetadata{
+		EventType: collectorinterface.CreateEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+		CASType:   "nfs-kernel",
+	}
+	tests := map[string]struct {
+		metadata          collectorinterface.EventMetadata
+		deliveryErrors    []error
+		expectedPhase     DeliveryPhase
+		expectedAttempts  int32
+		expectedLastError string
+		expectedAcks      int
+		isDelivered       bool
+	}{
+		"when event is delivered in first attempt": {
+			metadata:         metadata,
+			deliveryErrors:   []error{nil},
+			expectedPhase:    DeliveryPhaseSent,
+			expectedAttempts: 1,
+			expectedAcks:     1,
+			isDelivered:      true,
+		},
+		"when event is delivered after failure": {
+			metadata:         metadata,
+			deliveryErrors:   []error{errors.Errorf("broker not available"), nil},
+			expectedPhase:    DeliveryPhaseSent,
+			expectedAttempts: 2,
+			expectedAcks:     1,
+			isDelivered:      true,
+		},
+		"when event delivery is failed": {
+			metadata:          metadata,
+			deliveryErrors:    []error{errors.Errorf("timeout"), errors.Errorf("broker not available")},
+			expectedPhase:     DeliveryPhaseFailed,
+			expectedAttempts:  2,
+			expectedLastError: "broker not available",
+		},
+		"when delivery belongs to earlier volume with same name": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pv1",
+				PVUID:     "uid-2",
+			},
+			deliveryErrors:   []error{errors.Errorf("timeout")},
+			expectedPhase:    DeliveryPhaseFailed,
+			expectedAttempts: 1,
+			// Record of the earlier volume is created before test
+			expectedLastError: "timeout",
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			tracker := newFakeTracker(now)
+			if test.metadata.PVUID != metadata.PVUID {
+				if err := tracker.Record(metadata, nil); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+			for _, deliveryErr := range test.deliveryErrors {
+				if err := tracker.Record(test.metadata, deliveryErr); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+			delivery, err := tracker.Get(test.metadata.PVName, test.metadata.EventType)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if delivery == nil {
+				t.Fatalf("%q test failed expected delivery to exist", name)
+			}
+			if delivery.Name != "pv1-create" || delivery.Spec.PVUID != test.metadata.PVUID {
+				t.Errorf("%q test failed expected delivery pv1-create of %s but got %s of %s",
+					name, test.metadata.PVUID, delivery.Name, delivery.Spec.PVUID)
+			}
+			status := delivery.Status
+			if status.Phase != test.expectedPhase {
+				t.Errorf("%q test failed expected phase %s but got %s", name, test.expectedPhase, status.Phase)
+			}
+			if status.Attempts != test.expectedAttempts {
+				t.Errorf("%q test failed expected %d attempts but got %d", name, test.expectedAttempts, status.Attempts)
+			}
+			if status.LastError != test.expectedLastError {
+				t.Errorf("%q test failed expected last error %q but got %q", name, test.expectedLastError, status.LastError)
+			}
+			if len(status.Acknowledgements) != test.expectedAcks {
+				t.Errorf("%q test failed expected %d acknowledgements but got %v", name, test.expectedAcks, status.Acknowledgements)
+			}
+			if test.expectedAcks != 0 && status.Acknowledgements[0].Destination != "kafka" {
+				t.Errorf("%q test failed expected acknowledgement of kafka but got %v", name, status.Acknowledgements[0])
+			}
+			if status.LastAttemptTime == nil || !status.LastAttemptTime.Equal(&metav1.Time{Time: now}) {
+				t.Errorf("%q test failed expected last attempt time %v but got %v", name, now, status.LastAttemptTime)
+			}
+			isDelivered, err := tracker.IsDelivered(test.metadata)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if isDelivered != test.isDelivered {
+				t.Errorf("%q test failed expected delivered %t but got %t", name, test.isDelivered, isDelivered)
+			}
+		})
+	}
+}
+
+func TestRecordTruncatesLastError(t *testing.T) {
+	tracker := newFakeTracker(time.Now())
+	metadata := collectorinterface.EventMetadata{
+		EventType: collectorinterface.DeleteEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+	}
+	err := tracker.Record(metadata, errors.New(strings.Repeat("x", 2*maxLastErrorLength)))
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	deliveries, err := tracker.List()
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if len(deliveries) != 1 || len(deliveries[0].Status.LastError) != maxLastErrorLength {
+		t.Fatalf("expected one delivery with last error of length %d but got %v", maxLastErrorLength, deliveries)
+	}
+}
+
+func TestDrop(t *testing.T) {
+	metadata := collectorinterface.EventMetadata{
+		EventType: collectorinterface.DeleteEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+	}
+	tests := map[string]struct {
+		deliveryErrors []error
+		expectedPhase  DeliveryPhase
+	}{
+		"when event is never attempted": {
+			expectedPhase: DeliveryPhaseDropped,
+		},
+		"when event delivery is failed": {
+			deliveryErrors: []error{errors.Errorf("timeout")},
+			expectedPhase:  DeliveryPhaseDropped,
+		},
+		"when event is already delivered": {
+			deliveryErrors: []error{nil},
+			expectedPhase:  DeliveryPhaseSent,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			tracker := newFakeTracker(time.Now())
+			for _, deliveryErr := range test.deliveryErrors {
+				if err := tracker.Record(metadata, deliveryErr); err != nil {
+					t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+				}
+			}
+			if err := tracker.Drop(metadata, "finalizer released"); err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			delivery, err := tracker.Get(metadata.PVName, metadata.EventType)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if delivery == nil || delivery.Status.Phase != test.expectedPhase {
+				t.Fatalf("%q test failed expected delivery in phase %s but got %v", name, test.expectedPhase, delivery)
+			}
+			if test.expectedPhase == DeliveryPhaseDropped && delivery.Status.LastError != "finalizer released" {
+				t.Errorf("%q test failed expected last error %q but got %q", name, "finalizer released", delivery.Status.LastError)
+			}
+		})
+	}
+}
+
+func TestRecordCapsAcknowledgements(t *testing.T) {
+	tracker := newFakeTracker(time.Now())
+	metadata := collectorinterface.EventMetadata{
+		EventType: collectorinterface.CreateEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+	}
+	for i := 0; i < 2*maxAcknowledgements; i++ {
+		if err := tracker.Record(metadata, nil); err != nil {
+			t.Fatalf("expected error not to occur but got %v", err)
+		}
+	}
+	delivery, err := tracker.Get(metadata.PVName, metadata.EventType)
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if len(delivery.Status.Acknowledgements) != maxAcknowledgements {
+		t.Errorf("expected %d acknowledgements but got %d", maxAcknowledgements, len(delivery.Status.Acknowledgements))
+	}
+	if delivery.Status.Attempts != 2*maxAcknowledgements {
+		t.Errorf("expected %d attempts but got %d", 2*maxAcknowledgements, delivery.Status.Attempts)
+	}
+}
+
+func TestGetName(t *testing.T) {
+	longPVName := strings.Repeat("a", 250)
+	tests := map[string]struct {
+		pvName       string
+		eventType    collectorinterface.EventType
+		expectedName string
+	}{
+		"when name is within the limit": {
+			pvName:       "pv1",
+			eventType:    collectorinterface.CreateEventType,
+			expectedName: "pv1-create",
+		},
+		"when name exceeds the limit": {
+			pvName:    longPVName,
+			eventType: collectorinterface.DeleteEventType,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			deliveryName := GetName(test.pvName, test.eventType)
+			if test.expectedName != "" && deliveryName != test.expectedName {
+				t.Errorf("%q test failed expected name %s but got %s", name, test.expectedName, deliveryName)
+			}
+			if len(deliveryName) > maxNameLength || !strings.HasSuffix(deliveryName, "-"+string(test.eventType)) {
+				t.Errorf("%q test failed expected valid name of %s event but got %s", name, test.eventType, deliveryName)
+			}
+		})
+	}
+	// Shortened names of different volumes must not collide
+	if GetName(longPVName+"1", collectorinterface.CreateEventType) == GetName(longPVName+"2", collectorinterface.CreateEventType) {
+		t.Errorf("expected shortened names of different volumes to be different")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tracker := newFakeTracker(time.Now())
+	longPVName := strings.Repeat("a", 250)
+	for _, pvName := range []string{"pv1", "pv2", longPVName} {
+		for _, eventType := range []collectorinterface.EventType{collectorinterface.CreateEventType, collectorinterface.DeleteEventType} {
+			metadata := collectorinterface.EventMetadata{EventType: eventType, PVName: pvName, PVUID: "uid-" + pvName}
+			if err := tracker.Record(metadata, nil); err != nil {
+				t.Fatalf("expected error not to occur while recording delivery but got %v", err)
+			}
+		}
+	}
+	for _, pvName := range []string{"pv1", longPVName} {
+		if err := tracker.Delete(pvName); err != nil {
+			t.Fatalf("expected error not to occur while deleting deliveries of %s but got %v", pvName, err)
+		}
+	}
+	// Deleting deliveries of volume which doesn't have them is no-op
+	if err := tracker.Delete("pv3"); err != nil {
+		t.Fatalf("expected error not to occur while deleting deliveries of pv3 but got %v", err)
+	}
+	deliveries, err := tracker.List()
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("expected deliveries of only pv2 but got %v", deliveries)
+	}
+	for _, delivery := range deliveries {
+		if delivery.Spec.PVName != "pv2" {
+			t.Errorf("expected deliveries of only pv2 but got delivery %s of %s", delivery.Name, delivery.Spec.PVName)
+		}
+	}
+}
+
+func TestDropWhenEventIsDeliveredConcurrently(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{GroupVersionResource: Kind + "List"})
+	tracker := NewTracker(dynamicClient, "kafka")
+	metadata := collectorinterface.EventMetadata{
+		EventType: collectorinterface.DeleteEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+	}
+	if err := tracker.Record(metadata, errors.Errorf("timeout")); err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	// Worker delivers the event after sweeper has fetched the delivery,
+	// hence update of sweeper conflicts and delivered event is fetched
+	var deliveredObj *unstructured.Unstructured
+	var droppedUpdates int
+	dynamicClient.PrependReactor("update", "volumeeventdeliveries", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.UpdateAction).GetObject().(*unstructured.Unstructured)
+		if deliveredObj != nil {
+			droppedUpdates++
+			return false, nil, nil
+		}
+		deliveredObj = obj.DeepCopy()
+		if err := unstructured.SetNestedField(deliveredObj.Object, string(DeliveryPhaseSent), "status", "phase"); err != nil {
+			return true, nil, err
+		}
+		return true, nil, k8serrors.NewConflict(GroupVersionResource.GroupResource(), obj.GetName(), errors.Errorf("object has been modified"))
+	})
+	dynamicClient.PrependReactor("get", "volumeeventdeliveries", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if deliveredObj == nil {
+			return false, nil, nil
+		}
+		return true, deliveredObj.DeepCopy(), nil
+	})
+
+	if err := tracker.Drop(metadata, "finalizer released"); err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if deliveredObj == nil || droppedUpdates != 0 {
+		t.Fatalf("expected delivery delivered concurrently not to be dropped but got %d updates after conflict", droppedUpdates)
+	}
+	delivery, err := tracker.Get(metadata.PVName, metadata.EventType)
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if delivery == nil || delivery.Status.Phase != DeliveryPhaseSent {
+		t.Fatalf("expected delivery delivered concurrently to remain in phase %s but got %v", DeliveryPhaseSent, delivery)
+	}
+}
+
+func TestRecordRetriesOnConflict(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{GroupVersionResource: Kind + "List"})
+	tracker := NewTracker(dynamicClient, "kafka")
+	metadata := collectorinterface.EventMetadata{
+		EventType: collectorinterface.CreateEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+	}
+	if err := tracker.Record(metadata, errors.Errorf("timeout")); err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	// First update conflicts with a concurrent attempt
+	var conflicts int
+	dynamicClient.PrependReactor("update", "volumeeventdeliveries", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts != 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		obj := action.(k8stesting.UpdateAction).GetObject().(*unstructured.Unstructured)
+		return true, nil, k8serrors.NewConflict(GroupVersionResource.GroupResource(), obj.GetName(), errors.Errorf("object has been modified"))
+	})
+
+	if err := tracker.Record(metadata, nil); err != nil {
+		t.Fatalf("expected error not to occur after conflict but got %v", err)
+	}
+	delivery, err := tracker.Get(metadata.PVName, metadata.EventType)
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if conflicts != 1 || delivery.Status.Phase != DeliveryPhaseSent || delivery.Status.Attempts != 2 {
+		t.Fatalf("expected delivery to be recorded after %d conflicts but got %v", conflicts, delivery.Status)
+	}
+}
+
+func TestCreateSetsVolumeAsOwner(t *testing.T) {
+	tracker := newFakeTracker(time.Now())
+	metadata := collectorinterface.EventMetadata{
+		EventType: collectorinterface.CreateEventType,
+		PVName:    "pv1",
+		PVUID:     "uid-1",
+	}
+	if err := tracker.Record(metadata, nil); err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	delivery, err := tracker.Get(metadata.PVName, metadata.EventType)
+	if err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	if len(delivery.OwnerReferences) != 1 {
+		t.Fatalf("expected volume to be owner of delivery but got %v", delivery.OwnerReferences)
+	}
+	owner := delivery.OwnerReferences[0]
+	if owner.Kind != "PersistentVolume" || owner.Name != "pv1" || owner.UID != "uid-1" ||
+		owner.Controller == nil || !*owner.Controller {
+		t.Errorf("expected volume pv1 with UID uid-1 to be controller owner but got %v", owner)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName is the API group of VolumeEventDelivery resource
+	GroupName = "events.openebs.io"
+	// Version is the API version of VolumeEventDelivery resource
+	Version = "v1alpha1"
+	// Kind is the kind of VolumeEventDelivery resource
+	Kind = "VolumeEventDelivery"
+
+	// PVNameLabelKey holds the name of the volume of delivery, name is
+	// shortened if it exceeds the length allowed for label values
+	PVNameLabelKey = "events.openebs.io/pv-name"
+	// EventTypeLabelKey holds the type of the event of delivery
+	EventTypeLabelKey = "events.openebs.io/event-type"
+)
+
+// GroupVersionResource of cluster scoped VolumeEventDelivery resource
+var GroupVersionResource = schema.GroupVersionResource{
+	Group:    GroupName,
+	Version:  Version,
+	Resource: "volumeeventdeliveries",
+}
+
+// DeliveryPhase represents the state of event delivery
+type DeliveryPhase string
+
+const (
+	// DeliveryPhasePending states that event is not yet attempted
+	DeliveryPhasePending DeliveryPhase = "Pending"
+	// DeliveryPhaseSent states that event is accepted by the destination
+	DeliveryPhaseSent DeliveryPhase = "Sent"
+	// DeliveryPhaseFailed states that last attempt of delivery is failed,
+	// delivery will be retried by the exporter
+	DeliveryPhaseFailed DeliveryPhase = "Failed"
+	// DeliveryPhaseDropped states that event will never be delivered
+	// since finalizers of the volume are released forcefully
+	DeliveryPhaseDropped DeliveryPhase = "Dropped"
+)
+
+// VolumeEventDelivery records the delivery state of an event of
+// a volume. There will be one object per volume and event type
+type VolumeEventDelivery struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeEventDeliverySpec   `json:"spec"`
+	Status VolumeEventDeliveryStatus `json:"status,omitempty"`
+}
+
+// VolumeEventDeliverySpec identifies the event being delivered
+type VolumeEventDeliverySpec struct {
+	PVName    string `json:"pvName"`
+	PVUID     string `json:"pvUID"`
+	EventType string `json:"eventType"`
+	CASType   string `json:"casType,omitempty"`
+}
+
+// VolumeEventDeliveryStatus holds the delivery history of the event
+type VolumeEventDeliveryStatus struct {
+	Phase DeliveryPhase `json:"phase,omitempty"`
+	// Attempts is the number of attempts made to deliver the event
+	Attempts int32 `json:"attempts,omitempty"`
+	// LastError is the error of last failed attempt, it
+	// is cleared once event is delivered
+	LastError        string       `json:"lastError,omitempty"`
+	FirstAttemptTime *metav1.Time `json:"firstAttemptTime,omitempty"`
+	LastAttemptTime  *metav1.Time `json:"lastAttemptTime,omitempty"`
+	SentTime         *metav1.Time `json:"sentTime,omitempty"`
+	// Acknowledgements are recorded each time a destination
+	// accepts the event
+	Acknowledgements []Acknowledgement `json:"acknowledgements,omitempty"`
+}
+
+// Acknowledgement represents the acceptance of event by a destination
+type Acknowledgement struct {
+	// Destination is the sink type which accepted the event
+	Destination    string      `json:"destination"`
+	AcknowledgedAt metav1.Time `json:"acknowledgedAt"`
+}
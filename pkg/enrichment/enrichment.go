@@ -0,0 +1,51 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrichment
+
+import (
+	"k8s.io/client-go/tools/cache"
+)
+
+// Resolvers holds the optional resolvers used by collectors
+// to enrich volume events. Nil resolver disables the enrichment
+type Resolvers struct {
+	// Workload resolves the consumers of PVC
+	Workload *WorkloadResolver
+
+	// Tenant resolves the tenant fields of PVC
+	Tenant *TenantResolver
+
+	// StorageClass resolves the snapshots of StorageClasses of volumes
+	StorageClass *StorageClassResolver
+}
+
+// InformersSynced returns the functions which report whether caches
+// of enabled resolvers are synced. Events must not be collected until
+// caches are synced
+func (r Resolvers) InformersSynced() []cache.InformerSynced {
+	var informersSynced []cache.InformerSynced
+	if r.Workload != nil {
+		informersSynced = append(informersSynced, r.Workload.informersSynced...)
+	}
+	if r.Tenant != nil {
+		informersSynced = append(informersSynced, r.Tenant.informersSynced...)
+	}
+	if r.StorageClass != nil {
+		informersSynced = append(informersSynced, r.StorageClass.informersSynced...)
+	}
+	return informersSynced
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrichment
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	storagev1informer "k8s.io/client-go/informers/storage/v1"
+	storagev1listers "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// StorageClassSnapshot holds the details of StorageClass at the time
+// of collecting event so that receivers can use it even after the
+// StorageClass is deleted
+type StorageClassSnapshot struct {
+	Name                 string                                `json:"name"`
+	UID                  string                                `json:"uid"`
+	Provisioner          string                                `json:"provisioner"`
+	Parameters           map[string]string                     `json:"parameters,omitempty"`
+	ReclaimPolicy        *corev1.PersistentVolumeReclaimPolicy `json:"reclaim_policy,omitempty"`
+	VolumeBindingMode    *storagev1.VolumeBindingMode          `json:"volume_binding_mode,omitempty"`
+	AllowVolumeExpansion *bool                                 `json:"allow_volume_expansion,omitempty"`
+}
+
+// StorageClassResolver resolves the StorageClass snapshots from informer cache
+type StorageClassResolver struct {
+	storageClassLister storagev1listers.StorageClassLister
+	informersSynced    []cache.InformerSynced
+}
+
+// NewStorageClassResolver returns the resolver which uses given informer
+func NewStorageClassResolver(storageClassInformer storagev1informer.StorageClassInformer) *StorageClassResolver {
+	return &StorageClassResolver{
+		storageClassLister: storageClassInformer.Lister(),
+		informersSynced:    []cache.InformerSynced{storageClassInformer.Informer().HasSynced},
+	}
+}
+
+// Resolve returns the snapshot of given StorageClass. It returns nil
+// if name is empty or StorageClass doesn't exist
+func (s *StorageClassResolver) Resolve(name string) (*StorageClassSnapshot, error) {
+	if name == "" {
+		return nil, nil
+	}
+	storageClass, err := s.storageClassLister.Get(name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get StorageClass %s", name)
+	}
+	var parameters map[string]string
+	if len(storageClass.Parameters) != 0 {
+		parameters = make(map[string]string, len(storageClass.Parameters))
+		for key, value := range storageClass.Parameters {
+			parameters[key] = value
+		}
+	}
+	return &StorageClassSnapshot{
+		Name:                 storageClass.Name,
+		UID:                  string(storageClass.UID),
+		Provisioner:          storageClass.Provisioner,
+		Parameters:           parameters,
+		ReclaimPolicy:        storageClass.ReclaimPolicy,
+		VolumeBindingMode:    storageClass.VolumeBindingMode,
+		AllowVolumeExpansion: storageClass.AllowVolumeExpansion,
+	}, nil
+}
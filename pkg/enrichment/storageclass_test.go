@@ -0,0 +1,83 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrichment
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStorageClassResolverResolve(t *testing.T) {
+	reclaimPolicy := corev1.PersistentVolumeReclaimDelete
+	bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	storageClassInformer := kubeInformerFactory.Storage().V1().StorageClasses()
+	err := storageClassInformer.Informer().GetIndexer().Add(&storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "openebs-rwx",
+			UID:  "sc-uid-1",
+		},
+		Provisioner:       "openebs.io/nfsrwx",
+		Parameters:        map[string]string{"tier": "gold"},
+		ReclaimPolicy:     &reclaimPolicy,
+		VolumeBindingMode: &bindingMode,
+	})
+	if err != nil {
+		t.Fatalf("failed to add StorageClass into cache: %v", err)
+	}
+	resolver := NewStorageClassResolver(storageClassInformer)
+
+	tests := map[string]struct {
+		name             string
+		expectedSnapshot *StorageClassSnapshot
+	}{
+		"when StorageClass exists": {
+			name: "openebs-rwx",
+			expectedSnapshot: &StorageClassSnapshot{
+				Name:              "openebs-rwx",
+				UID:               "sc-uid-1",
+				Provisioner:       "openebs.io/nfsrwx",
+				Parameters:        map[string]string{"tier": "gold"},
+				ReclaimPolicy:     &reclaimPolicy,
+				VolumeBindingMode: &bindingMode,
+			},
+		},
+		"when StorageClass is deleted": {
+			name: "openebs-hostpath",
+		},
+		"when StorageClass name is empty": {},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			snapshot, err := resolver.Resolve(test.name)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if !cmp.Equal(snapshot, test.expectedSnapshot) {
+				t.Errorf("%q test failed expected snapshot %v but got %v", name, test.expectedSnapshot, snapshot)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrichment
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	corev1informer "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// tenantSource is the object metadata from which tenant field is read
+type tenantSource string
+
+const (
+	namespaceLabelSource tenantSource = "namespace-label"
+	pvcLabelSource       tenantSource = "pvc-label"
+	pvcAnnotationSource  tenantSource = "pvc-annotation"
+)
+
+// tenantField maps the tenant field to label or annotation key
+type tenantField struct {
+	name   string
+	source tenantSource
+	key    string
+}
+
+// TenantResolver resolves the tenant fields of PVC from its labels,
+// annotations and labels of its namespace
+type TenantResolver struct {
+	namespaceLister corev1listers.NamespaceLister
+	// fields are evaluated in order, first match of a field wins
+	fields          []tenantField
+	informersSynced []cache.InformerSynced
+}
+
+// NewTenantResolver parses given <field>=<source>:<key> mappings and returns
+// the resolver which uses given namespace informer to fetch namespaces
+func NewTenantResolver(namespaceInformer corev1informer.NamespaceInformer, mappings []string) (*TenantResolver, error) {
+	fields, err := parseTenantFields(mappings)
+	if err != nil {
+		return nil, err
+	}
+	return &TenantResolver{
+		namespaceLister: namespaceInformer.Lister(),
+		fields:          fields,
+		informersSynced: []cache.InformerSynced{namespaceInformer.Informer().HasSynced},
+	}, nil
+}
+
+func parseTenantFields(mappings []string) ([]tenantField, error) {
+	var fields []tenantField
+	for _, mapping := range mappings {
+		name, sourceKey := splitPair(mapping, "=")
+		source, key := splitPair(sourceKey, ":")
+		if name == "" || key == "" {
+			return nil, errors.Errorf("invalid tenant field mapping %q expected <field>=<source>:<key>", mapping)
+		}
+		switch tenantSource(source) {
+		case namespaceLabelSource, pvcLabelSource, pvcAnnotationSource:
+		default:
+			return nil, errors.Errorf("unsupported source %q of tenant field %s", source, name)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+			return nil, errors.Errorf("invalid key %q of tenant field %s: %s", key, name, strings.Join(errs, ", "))
+		}
+		fields = append(fields, tenantField{name: name, source: tenantSource(source), key: key})
+	}
+	return fields, nil
+}
+
+func splitPair(value, separator string) (string, string) {
+	pair := strings.SplitN(value, separator, 2)
+	if len(pair) != 2 {
+		return strings.TrimSpace(value), ""
+	}
+	return strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1])
+}
+
+// Resolve returns the tenant fields of the PVC. PVC can be nil if it is
+// already deleted, in that case only namespace labels are evaluated. Fields
+// which are not found are omitted
+func (t *TenantResolver) Resolve(namespace string, pvc *corev1.PersistentVolumeClaim) (map[string]string, error) {
+	namespaceObj, err := t.namespaceLister.Get(namespace)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+
+	tenant := map[string]string{}
+	for _, field := range t.fields {
+		if _, isExist := tenant[field.name]; isExist {
+			continue
+		}
+		var values map[string]string
+		switch field.source {
+		case namespaceLabelSource:
+			if namespaceObj != nil {
+				values = namespaceObj.Labels
+			}
+		case pvcLabelSource:
+			if pvc != nil {
+				values = pvc.Labels
+			}
+		case pvcAnnotationSource:
+			if pvc != nil {
+				values = pvc.Annotations
+			}
+		}
+		if value, isExist := values[field.key]; isExist {
+			tenant[field.name] = value
+		}
+	}
+	return tenant, nil
+}
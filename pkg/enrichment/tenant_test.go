@@ -0,0 +1,150 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrichment
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTenantResolverResolve(t *testing.T) {
+	mappings := []string{
+		"cost_center=namespace-label:billing.example.com/cost-center",
+		"team=pvc-label:team",
+		"team=namespace-label:team",
+		"project=pvc-annotation:example.com/project",
+	}
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	namespaceInformer := kubeInformerFactory.Core().V1().Namespaces()
+	err := namespaceInformer.Informer().GetIndexer().Add(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ns1",
+			Labels: map[string]string{
+				"billing.example.com/cost-center": "cc-42",
+				"team":                            "platform",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to add namespace into cache: %v", err)
+	}
+	resolver, err := NewTenantResolver(namespaceInformer, mappings)
+	if err != nil {
+		t.Fatalf("failed to create tenant resolver: %v", err)
+	}
+
+	tests := map[string]struct {
+		namespace      string
+		pvc            *corev1.PersistentVolumeClaim
+		expectedTenant map[string]string
+	}{
+		"when PVC label takes precedence over namespace label": {
+			namespace: "ns1",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pvc1",
+					Namespace:   "ns1",
+					Labels:      map[string]string{"team": "storage"},
+					Annotations: map[string]string{"example.com/project": "billing"},
+				},
+			},
+			expectedTenant: map[string]string{
+				"cost_center": "cc-42",
+				"team":        "storage",
+				"project":     "billing",
+			},
+		},
+		"when PVC doesn't exist namespace labels are used": {
+			namespace: "ns1",
+			expectedTenant: map[string]string{
+				"cost_center": "cc-42",
+				"team":        "platform",
+			},
+		},
+		"when namespace doesn't exist": {
+			namespace: "ns2",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pvc2",
+					Namespace: "ns2",
+					Labels:    map[string]string{"team": "storage"},
+				},
+			},
+			expectedTenant: map[string]string{
+				"team": "storage",
+			},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			tenant, err := resolver.Resolve(test.namespace, test.pvc)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if diff := cmp.Diff(test.expectedTenant, tenant); diff != "" {
+				t.Fatalf("%q test failed tenant mismatch (-want +got):\n%s", name, diff)
+			}
+		})
+	}
+}
+
+func TestParseTenantFields(t *testing.T) {
+	tests := map[string]struct {
+		mappings      []string
+		isErrExpected bool
+	}{
+		"when mappings are valid": {
+			mappings: []string{"team=pvc-label:team", "project=pvc-annotation:example.com/project"},
+		},
+		"when source is missing": {
+			mappings:      []string{"team=team"},
+			isErrExpected: true,
+		},
+		"when source is not supported": {
+			mappings:      []string{"team=node-label:team"},
+			isErrExpected: true,
+		},
+		"when field name is missing": {
+			mappings:      []string{"=pvc-label:team"},
+			isErrExpected: true,
+		},
+		"when key is invalid": {
+			mappings:      []string{"team=pvc-label:team/a/b"},
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := parseTenantFields(test.mappings)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+		})
+	}
+}
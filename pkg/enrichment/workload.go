@@ -0,0 +1,221 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrichment
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	appsv1informer "k8s.io/client-go/informers/apps/v1"
+	corev1informer "k8s.io/client-go/informers/core/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// podsByClaimIndex indexes pods by <namespace>/<claim-name>
+	// of PVCs mounted by the pod
+	podsByClaimIndex = "pods-by-claim"
+
+	replicaSetKind = "ReplicaSet"
+	deploymentKind = "Deployment"
+)
+
+// WorkloadContext holds the consumers of a PVC
+type WorkloadContext struct {
+	// Pods are the non terminated pods mounting the PVC
+	Pods []Pod `json:"pods"`
+	// Owners are the distinct workloads owning the pods
+	Owners []Owner `json:"owners"`
+	// StatefulSetTemplate is set if PVC is created from
+	// volumeClaimTemplates of a StatefulSet
+	StatefulSetTemplate *StatefulSetTemplate `json:"statefulset_template,omitempty"`
+}
+
+// Pod holds the details of pod mounting the PVC
+type Pod struct {
+	Name      string          `json:"name"`
+	Namespace string          `json:"namespace"`
+	UID       string          `json:"uid"`
+	NodeName  string          `json:"node_name,omitempty"`
+	Phase     corev1.PodPhase `json:"phase"`
+	// Owner is the top level workload owning the pod
+	Owner *Owner `json:"owner,omitempty"`
+}
+
+// Owner holds the details of workload resolved through owner references
+// ex: Deployment(via ReplicaSet), StatefulSet, Job or DaemonSet
+type Owner struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
+}
+
+// StatefulSetTemplate identifies the volumeClaimTemplate
+// of StatefulSet from which PVC is created
+type StatefulSetTemplate struct {
+	StatefulSetName string `json:"statefulset_name"`
+	TemplateName    string `json:"template_name"`
+	Ordinal         int    `json:"ordinal"`
+}
+
+// WorkloadResolver resolves the consumers of PVC from informer
+// caches so that enrichment doesn't make calls to API server
+type WorkloadResolver struct {
+	podIndexer        cache.Indexer
+	replicaSetLister  appsv1listers.ReplicaSetLister
+	statefulSetLister appsv1listers.StatefulSetLister
+	informersSynced   []cache.InformerSynced
+}
+
+// NewWorkloadResolver returns the resolver which uses given informers.
+// It must be called before starting the informers since it registers
+// the index of pods by claim
+func NewWorkloadResolver(
+	podInformer corev1informer.PodInformer,
+	replicaSetInformer appsv1informer.ReplicaSetInformer,
+	statefulSetInformer appsv1informer.StatefulSetInformer) (*WorkloadResolver, error) {
+	err := podInformer.Informer().AddIndexers(cache.Indexers{podsByClaimIndex: indexPodsByClaim})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to add %s index on pod informer", podsByClaimIndex)
+	}
+	return &WorkloadResolver{
+		podIndexer:        podInformer.Informer().GetIndexer(),
+		replicaSetLister:  replicaSetInformer.Lister(),
+		statefulSetLister: statefulSetInformer.Lister(),
+		informersSynced: []cache.InformerSynced{
+			podInformer.Informer().HasSynced,
+			replicaSetInformer.Informer().HasSynced,
+			statefulSetInformer.Informer().HasSynced,
+		},
+	}, nil
+}
+
+func indexPodsByClaim(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+	var keys []string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			keys = append(keys, pod.Namespace+"/"+volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return keys, nil
+}
+
+// Resolve returns the consumers of given PVC
+func (w *WorkloadResolver) Resolve(namespace, pvcName string) (*WorkloadContext, error) {
+	objs, err := w.podIndexer.ByIndex(podsByClaimIndex, namespace+"/"+pvcName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list pods mounting PVC %s/%s", namespace, pvcName)
+	}
+
+	workloadContext := &WorkloadContext{
+		Pods:   []Pod{},
+		Owners: []Owner{},
+	}
+	isOwnerExist := map[string]bool{}
+	for _, obj := range objs {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		owner, err := w.getOwner(pod)
+		if err != nil {
+			return nil, err
+		}
+		workloadContext.Pods = append(workloadContext.Pods, Pod{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			UID:       string(pod.UID),
+			NodeName:  pod.Spec.NodeName,
+			Phase:     pod.Status.Phase,
+			Owner:     owner,
+		})
+		if owner != nil && !isOwnerExist[owner.UID] {
+			isOwnerExist[owner.UID] = true
+			workloadContext.Owners = append(workloadContext.Owners, *owner)
+		}
+	}
+
+	workloadContext.StatefulSetTemplate, err = w.getStatefulSetTemplate(namespace, pvcName)
+	if err != nil {
+		return nil, err
+	}
+	return workloadContext, nil
+}
+
+// getOwner returns the top level controller of the pod. Pods owned
+// by ReplicaSet are resolved to Deployment owning the ReplicaSet
+func (w *WorkloadResolver) getOwner(pod *corev1.Pod) (*Owner, error) {
+	ownerRef := metav1.GetControllerOf(pod)
+	if ownerRef == nil {
+		return nil, nil
+	}
+	if ownerRef.Kind == replicaSetKind {
+		replicaSet, err := w.replicaSetLister.ReplicaSets(pod.Namespace).Get(ownerRef.Name)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get ReplicaSet %s/%s", pod.Namespace, ownerRef.Name)
+		}
+		if replicaSet != nil {
+			if deploymentRef := metav1.GetControllerOf(replicaSet); deploymentRef != nil && deploymentRef.Kind == deploymentKind {
+				ownerRef = deploymentRef
+			}
+		}
+	}
+	return &Owner{
+		APIVersion: ownerRef.APIVersion,
+		Kind:       ownerRef.Kind,
+		Name:       ownerRef.Name,
+		UID:        string(ownerRef.UID),
+	}, nil
+}
+
+// getStatefulSetTemplate returns the volumeClaimTemplate from which PVC is
+// created. StatefulSet controller names PVC as <template>-<statefulset>-<ordinal>
+func (w *WorkloadResolver) getStatefulSetTemplate(namespace, pvcName string) (*StatefulSetTemplate, error) {
+	statefulSets, err := w.statefulSetLister.StatefulSets(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list StatefulSets in namespace %s", namespace)
+	}
+	for _, statefulSet := range statefulSets {
+		for _, claimTemplate := range statefulSet.Spec.VolumeClaimTemplates {
+			prefix := claimTemplate.Name + "-" + statefulSet.Name + "-"
+			if !strings.HasPrefix(pvcName, prefix) {
+				continue
+			}
+			ordinal, err := strconv.Atoi(strings.TrimPrefix(pvcName, prefix))
+			if err != nil || ordinal < 0 {
+				continue
+			}
+			return &StatefulSetTemplate{
+				StatefulSetName: statefulSet.Name,
+				TemplateName:    claimTemplate.Name,
+				Ordinal:         ordinal,
+			}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrichment
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newControllerRef(apiVersion, kind, name, uid string) []metav1.OwnerReference {
+	isController := true
+	return []metav1.OwnerReference{
+		{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID(uid), Controller: &isController},
+	}
+}
+
+func newPod(name, claimName string, phase corev1.PodPhase, ownerRefs []metav1.OwnerReference) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "ns1",
+			UID:             types.UID(name + "-uid"),
+			OwnerReferences: ownerRefs,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node1",
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func newWorkloadResolver(t *testing.T, objs ...interface{}) *WorkloadResolver {
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	replicaSetInformer := kubeInformerFactory.Apps().V1().ReplicaSets()
+	statefulSetInformer := kubeInformerFactory.Apps().V1().StatefulSets()
+	resolver, err := NewWorkloadResolver(podInformer, replicaSetInformer, statefulSetInformer)
+	if err != nil {
+		t.Fatalf("failed to create workload resolver: %v", err)
+	}
+	for _, obj := range objs {
+		var err error
+		switch obj := obj.(type) {
+		case *corev1.Pod:
+			err = podInformer.Informer().GetIndexer().Add(obj)
+		case *appsv1.ReplicaSet:
+			err = replicaSetInformer.Informer().GetIndexer().Add(obj)
+		case *appsv1.StatefulSet:
+			err = statefulSetInformer.Informer().GetIndexer().Add(obj)
+		}
+		if err != nil {
+			t.Fatalf("failed to add %v into cache: %v", obj, err)
+		}
+	}
+	return resolver
+}
+
+func TestWorkloadResolverResolve(t *testing.T) {
+	deploymentOwner := Owner{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "web-uid"}
+	statefulSetOwner := Owner{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db", UID: "db-uid"}
+	jobOwner := Owner{APIVersion: "batch/v1", Kind: "Job", Name: "backup", UID: "backup-uid"}
+	resolver := newWorkloadResolver(t,
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "web-5d8f",
+				Namespace:       "ns1",
+				OwnerReferences: newControllerRef("apps/v1", "Deployment", "web", "web-uid"),
+			},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ns1"},
+			Spec: appsv1.StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				},
+			},
+		},
+		newPod("web-5d8f-a", "shared", corev1.PodRunning, newControllerRef("apps/v1", "ReplicaSet", "web-5d8f", "rs-uid")),
+		newPod("web-5d8f-b", "shared", corev1.PodPending, newControllerRef("apps/v1", "ReplicaSet", "web-5d8f", "rs-uid")),
+		newPod("backup-x", "shared", corev1.PodRunning, newControllerRef("batch/v1", "Job", "backup", "backup-uid")),
+		newPod("backup-old", "shared", corev1.PodSucceeded, newControllerRef("batch/v1", "Job", "backup", "backup-uid")),
+		newPod("db-0", "data-db-0", corev1.PodRunning, newControllerRef("apps/v1", "StatefulSet", "db", "db-uid")),
+		newPod("debug", "data-db-1", corev1.PodRunning, nil),
+	)
+
+	tests := map[string]struct {
+		pvcName         string
+		expectedContext *WorkloadContext
+	}{
+		"when PVC is mounted by pods of Deployment and Job": {
+			pvcName: "shared",
+			expectedContext: &WorkloadContext{
+				Pods: []Pod{
+					{Name: "backup-x", Namespace: "ns1", UID: "backup-x-uid", NodeName: "node1", Phase: corev1.PodRunning, Owner: &jobOwner},
+					{Name: "web-5d8f-a", Namespace: "ns1", UID: "web-5d8f-a-uid", NodeName: "node1", Phase: corev1.PodRunning, Owner: &deploymentOwner},
+					{Name: "web-5d8f-b", Namespace: "ns1", UID: "web-5d8f-b-uid", NodeName: "node1", Phase: corev1.PodPending, Owner: &deploymentOwner},
+				},
+				Owners: []Owner{jobOwner, deploymentOwner},
+			},
+		},
+		"when PVC is created from StatefulSet volumeClaimTemplates": {
+			pvcName: "data-db-0",
+			expectedContext: &WorkloadContext{
+				Pods: []Pod{
+					{Name: "db-0", Namespace: "ns1", UID: "db-0-uid", NodeName: "node1", Phase: corev1.PodRunning, Owner: &statefulSetOwner},
+				},
+				Owners: []Owner{statefulSetOwner},
+				StatefulSetTemplate: &StatefulSetTemplate{
+					StatefulSetName: "db",
+					TemplateName:    "data",
+					Ordinal:         0,
+				},
+			},
+		},
+		"when StatefulSet PVC is mounted by pod without owner": {
+			pvcName: "data-db-1",
+			expectedContext: &WorkloadContext{
+				Pods: []Pod{
+					{Name: "debug", Namespace: "ns1", UID: "debug-uid", NodeName: "node1", Phase: corev1.PodRunning},
+				},
+				Owners: []Owner{},
+				StatefulSetTemplate: &StatefulSetTemplate{
+					StatefulSetName: "db",
+					TemplateName:    "data",
+					Ordinal:         1,
+				},
+			},
+		},
+		"when PVC is not mounted by any pod": {
+			pvcName: "data-db-x",
+			expectedContext: &WorkloadContext{
+				Pods:   []Pod{},
+				Owners: []Owner{},
+			},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			workloadContext, err := resolver.Resolve("ns1", test.pvcName)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			// Pods are returned in the order of cache, sort them for comparison
+			sortWorkloadContext(workloadContext)
+			sortWorkloadContext(test.expectedContext)
+			if diff := cmp.Diff(test.expectedContext, workloadContext); diff != "" {
+				t.Fatalf("%q test failed workload context mismatch (-want +got):\n%s", name, diff)
+			}
+		})
+	}
+}
+
+func sortWorkloadContext(workloadContext *WorkloadContext) {
+	sort.Slice(workloadContext.Pods, func(i, j int) bool {
+		return workloadContext.Pods[i].Name < workloadContext.Pods[j].Name
+	})
+	sort.Slice(workloadContext.Owners, func(i, j int) bool {
+		return workloadContext.Owners[i].Name < workloadContext.Owners[j].Name
+	})
+}
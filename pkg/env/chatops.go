@@ -0,0 +1,67 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ChatOpsWebhookURL defines the incoming webhook URL to which messages are posted
+	ChatOpsWebhookURL = "CHATOPS_WEBHOOK_URL"
+
+	// ChatOpsPlatform defines the shape of the webhook payload. Supported
+	// values are slack, mattermost and teams
+	ChatOpsPlatform = "CHATOPS_PLATFORM"
+
+	// ChatOpsTemplateFile defines the path of Go template used to
+	// render the message instead of the default one
+	ChatOpsTemplateFile = "CHATOPS_TEMPLATE_FILE"
+
+	// ChatOpsTimeout defines the deadline in seconds of a webhook request
+	ChatOpsTimeout = "CHATOPS_TIMEOUT"
+)
+
+const (
+	defaultChatOpsPlatform = "slack"
+	defaultChatOpsTimeout  = 30 * time.Second
+)
+
+func GetChatOpsWebhookURL() string {
+	return strings.TrimSpace(os.Getenv(ChatOpsWebhookURL))
+}
+
+func GetChatOpsPlatform() string {
+	return strings.ToLower(getOrDefault(ChatOpsPlatform, defaultChatOpsPlatform))
+}
+
+func GetChatOpsTemplateFile() string {
+	return strings.TrimSpace(os.Getenv(ChatOpsTemplateFile))
+}
+
+// GetChatOpsTimeout returns the deadline of webhook request. If
+// missing or invalid then defaults to 30 seconds
+func GetChatOpsTimeout() time.Duration {
+	timeout, err := strconv.Atoi(strings.TrimSpace(os.Getenv(ChatOpsTimeout)))
+	if err != nil || timeout <= 0 {
+		return defaultChatOpsTimeout
+	}
+	return time.Duration(timeout) * time.Second
+}
@@ -18,6 +18,7 @@ package env
 
 import (
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -25,6 +26,15 @@ var (
 	// NFSServerNamespace defines the namespace of NFS Server resources
 	NFSServerNamespace = "OPENEBS_IO_NFS_SERVER_NS"
 
+	// NFSBackingPVCNamespaceKey defines the annotation or label key on NFS PV
+	// which overrides the namespace of backing PVC. It is not set by default
+	// and backing PVC is discovered from labels set by NFS provisioner
+	NFSBackingPVCNamespaceKey = "NFS_BACKING_PVC_NAMESPACE_KEY"
+
+	// NFSBackingPVCNameKey defines the annotation or label key on NFS PV
+	// which overrides the name of backing PVC. It is not set by default
+	NFSBackingPVCNameKey = "NFS_BACKING_PVC_NAME_KEY"
+
 	// OpenEBSNamespace defines the namespace where pod is running
 	// This environment variable set via Kubernetes downward API
 	OpenEBSNamespace = "OPENEBS_NAMESPACE"
@@ -34,6 +44,10 @@ var (
 
 	// ServerCallBackToken defines the server authentication token
 	ServerCallBackAuthToken = "CALLBACK_TOKEN"
+
+	// EventsSinkType defines the type of the sink to which volume events
+	// are exported. Defaults to token based REST server
+	EventsSinkType = "EVENTS_SINK_TYPE"
 )
 
 func GetNFSServerNamespace() string {
@@ -44,6 +58,14 @@ func GetNFSServerNamespace() string {
 	return os.Getenv(OpenEBSNamespace)
 }
 
+func GetNFSBackingPVCNamespaceKey() string {
+	return strings.TrimSpace(os.Getenv(NFSBackingPVCNamespaceKey))
+}
+
+func GetNFSBackingPVCNameKey() string {
+	return strings.TrimSpace(os.Getenv(NFSBackingPVCNameKey))
+}
+
 func GetCallBackServerURL() string {
 	return strings.TrimSpace(os.Getenv(ServerCallBackURL))
 }
@@ -51,3 +73,35 @@ func GetCallBackServerURL() string {
 func GetCallBackServerAuthToken() string {
 	return strings.TrimSpace(os.Getenv(ServerCallBackAuthToken))
 }
+
+func GetEventsSinkType() string {
+	return strings.TrimSpace(os.Getenv(EventsSinkType))
+}
+
+// getOrDefault returns value of the given environment variable
+// or defaultValue if it is not set
+func getOrDefault(envName, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(envName)); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getBool returns true only if given environment variable
+// holds a valid truthy value
+func getBool(envName string) bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(envName)))
+	return err == nil && value
+}
+
+// getList returns the non empty values of given comma
+// separated environment variable
+func getList(envName string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(envName), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ExecSinkCommand defines the path of the executable invoked for each event
+	ExecSinkCommand = "EXEC_SINK_COMMAND"
+
+	// ExecSinkArgs defines space separated arguments passed to the executable
+	ExecSinkArgs = "EXEC_SINK_ARGS"
+
+	// ExecSinkTimeout defines the time in seconds after which executable is killed
+	ExecSinkTimeout = "EXEC_SINK_TIMEOUT"
+
+	// ExecSinkEnvAllowList defines comma separated names of environment
+	// variables passed to the executable in addition to PATH
+	ExecSinkEnvAllowList = "EXEC_SINK_ENV_ALLOWLIST"
+)
+
+const (
+	defaultExecSinkTimeout = 60 * time.Second
+)
+
+func GetExecSinkCommand() string {
+	return strings.TrimSpace(os.Getenv(ExecSinkCommand))
+}
+
+func GetExecSinkArgs() []string {
+	return strings.Fields(os.Getenv(ExecSinkArgs))
+}
+
+// GetExecSinkTimeout returns the timeout of the executable. If missing
+// or invalid then defaults to 60 seconds
+func GetExecSinkTimeout() time.Duration {
+	timeout, err := strconv.Atoi(strings.TrimSpace(os.Getenv(ExecSinkTimeout)))
+	if err != nil || timeout <= 0 {
+		return defaultExecSinkTimeout
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+func GetExecSinkEnvAllowList() []string {
+	return getList(ExecSinkEnvAllowList)
+}
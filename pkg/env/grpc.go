@@ -0,0 +1,85 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// GRPCServerAddress defines the address(host:port) of gRPC server
+	GRPCServerAddress = "GRPC_SERVER_ADDRESS"
+
+	// GRPCAuthToken defines the token sent as metadata to authenticate with server
+	GRPCAuthToken = "GRPC_AUTH_TOKEN"
+
+	// GRPCTimeout defines the deadline in seconds of a publish call
+	GRPCTimeout = "GRPC_TIMEOUT"
+
+	// GRPCTLSEnabled enables TLS while connecting to the server
+	GRPCTLSEnabled = "GRPC_TLS_ENABLED"
+
+	// GRPCTLSCAFile defines the path of CA certificate to verify the server
+	GRPCTLSCAFile = "GRPC_TLS_CA_FILE"
+
+	// GRPCTLSCertFile defines the path of client certificate
+	GRPCTLSCertFile = "GRPC_TLS_CERT_FILE"
+
+	// GRPCTLSKeyFile defines the path of client key
+	GRPCTLSKeyFile = "GRPC_TLS_KEY_FILE"
+)
+
+const (
+	defaultGRPCTimeout = 30 * time.Second
+)
+
+func GetGRPCServerAddress() string {
+	return strings.TrimSpace(os.Getenv(GRPCServerAddress))
+}
+
+func GetGRPCAuthToken() string {
+	return strings.TrimSpace(os.Getenv(GRPCAuthToken))
+}
+
+// GetGRPCTimeout returns the deadline of publish call. If missing
+// or invalid then defaults to 30 seconds
+func GetGRPCTimeout() time.Duration {
+	timeout, err := strconv.Atoi(strings.TrimSpace(os.Getenv(GRPCTimeout)))
+	if err != nil || timeout <= 0 {
+		return defaultGRPCTimeout
+	}
+	return time.Duration(timeout) * time.Second
+}
+
+func IsGRPCTLSEnabled() bool {
+	return getBool(GRPCTLSEnabled)
+}
+
+func GetGRPCTLSCAFile() string {
+	return strings.TrimSpace(os.Getenv(GRPCTLSCAFile))
+}
+
+func GetGRPCTLSCertFile() string {
+	return strings.TrimSpace(os.Getenv(GRPCTLSCertFile))
+}
+
+func GetGRPCTLSKeyFile() string {
+	return strings.TrimSpace(os.Getenv(GRPCTLSKeyFile))
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// FileSinkPath defines the path of the file to which events are appended
+	FileSinkPath = "FILE_SINK_PATH"
+
+	// FileSinkMaxSizeMB defines the size in megabytes after which file is rotated
+	FileSinkMaxSizeMB = "FILE_SINK_MAX_SIZE_MB"
+
+	// FileSinkRotationInterval defines the interval in seconds after which
+	// file is rotated. Time based rotation is disabled if it is set to 0
+	FileSinkRotationInterval = "FILE_SINK_ROTATION_INTERVAL"
+
+	// FileSinkCompress enables gzip compression of rotated files
+	FileSinkCompress = "FILE_SINK_COMPRESS"
+)
+
+const (
+	defaultFileSinkPath             = "/var/lib/volume-events-exporter/events.jsonl"
+	defaultFileSinkMaxSizeMB        = 100
+	defaultFileSinkRotationInterval = 24 * time.Hour
+)
+
+func GetFileSinkPath() string {
+	return getOrDefault(FileSinkPath, defaultFileSinkPath)
+}
+
+// GetFileSinkMaxSize returns the max size of file in bytes. If
+// missing or invalid then defaults to 100MB
+func GetFileSinkMaxSize() int64 {
+	maxSizeMB, err := strconv.ParseInt(strings.TrimSpace(os.Getenv(FileSinkMaxSizeMB)), 10, 64)
+	if err != nil || maxSizeMB <= 0 {
+		maxSizeMB = defaultFileSinkMaxSizeMB
+	}
+	return maxSizeMB * 1024 * 1024
+}
+
+// GetFileSinkRotationInterval returns the rotation interval. If missing
+// or invalid then defaults to 24 hours
+func GetFileSinkRotationInterval() time.Duration {
+	interval, err := strconv.Atoi(strings.TrimSpace(os.Getenv(FileSinkRotationInterval)))
+	if err != nil || interval < 0 {
+		return defaultFileSinkRotationInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+func IsFileSinkCompressEnabled() bool {
+	return getBool(FileSinkCompress)
+}
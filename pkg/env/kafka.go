@@ -0,0 +1,107 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+var (
+	// KafkaBrokers defines comma separated list of Kafka broker addresses
+	KafkaBrokers = "KAFKA_BROKERS"
+
+	// KafkaCreateEventTopic defines the topic to publish volume create events
+	KafkaCreateEventTopic = "KAFKA_CREATE_EVENT_TOPIC"
+
+	// KafkaDeleteEventTopic defines the topic to publish volume delete events
+	KafkaDeleteEventTopic = "KAFKA_DELETE_EVENT_TOPIC"
+
+	// KafkaSASLMechanism defines the SASL mechanism(PLAIN, SCRAM-SHA-256, SCRAM-SHA-512).
+	// SASL authentication is disabled if it is empty
+	KafkaSASLMechanism = "KAFKA_SASL_MECHANISM"
+
+	// KafkaSASLUser defines the SASL user name
+	KafkaSASLUser = "KAFKA_SASL_USER"
+
+	// KafkaSASLPassword defines the SASL password
+	KafkaSASLPassword = "KAFKA_SASL_PASSWORD"
+
+	// KafkaTLSEnabled enables TLS while connecting to brokers
+	KafkaTLSEnabled = "KAFKA_TLS_ENABLED"
+
+	// KafkaTLSCAFile defines the path of CA certificate to verify brokers
+	KafkaTLSCAFile = "KAFKA_TLS_CA_FILE"
+
+	// KafkaTLSCertFile defines the path of client certificate
+	KafkaTLSCertFile = "KAFKA_TLS_CERT_FILE"
+
+	// KafkaTLSKeyFile defines the path of client key
+	KafkaTLSKeyFile = "KAFKA_TLS_KEY_FILE"
+
+	// KafkaTLSInsecureSkipVerify disables verification of broker certificates
+	KafkaTLSInsecureSkipVerify = "KAFKA_TLS_INSECURE_SKIP_VERIFY"
+)
+
+const (
+	defaultKafkaCreateEventTopic = "volume-create-events"
+	defaultKafkaDeleteEventTopic = "volume-delete-events"
+)
+
+func GetKafkaBrokers() []string {
+	return getList(KafkaBrokers)
+}
+
+func GetKafkaCreateEventTopic() string {
+	return getOrDefault(KafkaCreateEventTopic, defaultKafkaCreateEventTopic)
+}
+
+func GetKafkaDeleteEventTopic() string {
+	return getOrDefault(KafkaDeleteEventTopic, defaultKafkaDeleteEventTopic)
+}
+
+func GetKafkaSASLMechanism() string {
+	return strings.TrimSpace(os.Getenv(KafkaSASLMechanism))
+}
+
+func GetKafkaSASLUser() string {
+	return strings.TrimSpace(os.Getenv(KafkaSASLUser))
+}
+
+func GetKafkaSASLPassword() string {
+	return strings.TrimSpace(os.Getenv(KafkaSASLPassword))
+}
+
+func IsKafkaTLSEnabled() bool {
+	return getBool(KafkaTLSEnabled)
+}
+
+func GetKafkaTLSCAFile() string {
+	return strings.TrimSpace(os.Getenv(KafkaTLSCAFile))
+}
+
+func GetKafkaTLSCertFile() string {
+	return strings.TrimSpace(os.Getenv(KafkaTLSCertFile))
+}
+
+func GetKafkaTLSKeyFile() string {
+	return strings.TrimSpace(os.Getenv(KafkaTLSKeyFile))
+}
+
+func IsKafkaTLSInsecureSkipVerify() bool {
+	return getBool(KafkaTLSInsecureSkipVerify)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+var (
+	// NATSURL defines comma separated list of NATS server URLs
+	NATSURL = "NATS_URL"
+
+	// NATSSubjectTemplate defines Go template to build the subject of an event
+	NATSSubjectTemplate = "NATS_SUBJECT_TEMPLATE"
+
+	// NATSCredsFile defines the path of NATS user credentials file
+	NATSCredsFile = "NATS_CREDS_FILE"
+
+	// NATSTLSCAFile defines the path of CA certificate to verify NATS servers
+	NATSTLSCAFile = "NATS_TLS_CA_FILE"
+
+	// NATSTLSCertFile defines the path of client certificate
+	NATSTLSCertFile = "NATS_TLS_CERT_FILE"
+
+	// NATSTLSKeyFile defines the path of client key
+	NATSTLSKeyFile = "NATS_TLS_KEY_FILE"
+
+	// NATSContentType defines the Content-Type header of published messages
+	NATSContentType = "NATS_CONTENT_TYPE"
+)
+
+const (
+	defaultNATSSubjectTemplate = "volumes.{{ .CASType }}.{{ .Action }}"
+	defaultNATSContentType     = "application/json"
+)
+
+func GetNATSURL() string {
+	return strings.TrimSpace(os.Getenv(NATSURL))
+}
+
+func GetNATSSubjectTemplate() string {
+	return getOrDefault(NATSSubjectTemplate, defaultNATSSubjectTemplate)
+}
+
+func GetNATSCredsFile() string {
+	return strings.TrimSpace(os.Getenv(NATSCredsFile))
+}
+
+func GetNATSTLSCAFile() string {
+	return strings.TrimSpace(os.Getenv(NATSTLSCAFile))
+}
+
+func GetNATSTLSCertFile() string {
+	return strings.TrimSpace(os.Getenv(NATSTLSCertFile))
+}
+
+func GetNATSTLSKeyFile() string {
+	return strings.TrimSpace(os.Getenv(NATSTLSKeyFile))
+}
+
+func GetNATSContentType() string {
+	return getOrDefault(NATSContentType, defaultNATSContentType)
+}
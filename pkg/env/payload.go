@@ -0,0 +1,120 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+var (
+	// PayloadTemplateFile defines the path of Go template used
+	// to render the payload sent to the configured sink
+	PayloadTemplateFile = "PAYLOAD_TEMPLATE_FILE"
+
+	// PayloadJSONPath defines the JSONPath template used to render
+	// the payload sent to the configured sink
+	PayloadJSONPath = "PAYLOAD_JSONPATH"
+
+	// PayloadSchema defines the schema of payload sent to the configured
+	// sink. Supported values are raw(default) and compact
+	PayloadSchema = "PAYLOAD_SCHEMA"
+
+	// PayloadKeepManagedFields retains metadata.managedFields of
+	// Kubernetes objects in payload which are dropped by default
+	PayloadKeepManagedFields = "PAYLOAD_KEEP_MANAGED_FIELDS"
+
+	// PayloadLabelAllowList defines comma separated glob patterns of
+	// label keys to retain in payload. All labels are retained if empty
+	PayloadLabelAllowList = "PAYLOAD_LABEL_ALLOW_LIST"
+
+	// PayloadLabelDenyList defines comma separated glob patterns of
+	// label keys to drop from payload
+	PayloadLabelDenyList = "PAYLOAD_LABEL_DENY_LIST"
+
+	// PayloadAnnotationAllowList defines comma separated glob patterns of
+	// annotation keys to retain in payload. All annotations are retained if empty
+	PayloadAnnotationAllowList = "PAYLOAD_ANNOTATION_ALLOW_LIST"
+
+	// PayloadAnnotationDenyList defines comma separated glob patterns of
+	// annotation keys to drop from payload
+	PayloadAnnotationDenyList = "PAYLOAD_ANNOTATION_DENY_LIST"
+
+	// PayloadRedactFields defines comma separated dotted paths of fields,
+	// relative to each Kubernetes object in payload, to redact
+	PayloadRedactFields = "PAYLOAD_REDACT_FIELDS"
+)
+
+const (
+	defaultPayloadSchema = "raw"
+)
+
+// SinkEnvName returns the name of environment variable which overrides
+// given payload variable for the sink ex: PAYLOAD_TEMPLATE_FILE_KAFKA
+func SinkEnvName(envName, sinkType string) string {
+	return envName + "_" + strings.ToUpper(sinkType)
+}
+
+func GetPayloadTemplateFile() string {
+	return strings.TrimSpace(os.Getenv(PayloadTemplateFile))
+}
+
+func GetPayloadJSONPath() string {
+	return strings.TrimSpace(os.Getenv(PayloadJSONPath))
+}
+
+func GetSinkPayloadTemplateFile(sinkType string) string {
+	return strings.TrimSpace(os.Getenv(SinkEnvName(PayloadTemplateFile, sinkType)))
+}
+
+func GetSinkPayloadJSONPath(sinkType string) string {
+	return strings.TrimSpace(os.Getenv(SinkEnvName(PayloadJSONPath, sinkType)))
+}
+
+func GetPayloadSchema() string {
+	return strings.ToLower(getOrDefault(PayloadSchema, defaultPayloadSchema))
+}
+
+// GetSinkPayloadSchema returns the payload schema of given
+// sink, it is empty if schema is not configured for the sink
+func GetSinkPayloadSchema(sinkType string) string {
+	return strings.ToLower(strings.TrimSpace(os.Getenv(SinkEnvName(PayloadSchema, sinkType))))
+}
+
+func IsPayloadKeepManagedFieldsEnabled() bool {
+	return getBool(PayloadKeepManagedFields)
+}
+
+func GetPayloadLabelAllowList() []string {
+	return getList(PayloadLabelAllowList)
+}
+
+func GetPayloadLabelDenyList() []string {
+	return getList(PayloadLabelDenyList)
+}
+
+func GetPayloadAnnotationAllowList() []string {
+	return getList(PayloadAnnotationAllowList)
+}
+
+func GetPayloadAnnotationDenyList() []string {
+	return getList(PayloadAnnotationDenyList)
+}
+
+func GetPayloadRedactFields() []string {
+	return getList(PayloadRedactFields)
+}
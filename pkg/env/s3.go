@@ -0,0 +1,132 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// S3Endpoint defines the endpoint(host:port) of S3 compatible object storage
+	S3Endpoint = "S3_ENDPOINT"
+
+	// S3Bucket defines the bucket to which events are written
+	S3Bucket = "S3_BUCKET"
+
+	// S3Region defines the region of the bucket
+	S3Region = "S3_REGION"
+
+	// S3AccessKeyID defines the access key used to sign the requests
+	S3AccessKeyID = "S3_ACCESS_KEY_ID"
+
+	// S3SecretAccessKey defines the secret key used to sign the requests
+	S3SecretAccessKey = "S3_SECRET_ACCESS_KEY"
+
+	// S3PathStyle enables path style(endpoint/bucket/key) requests
+	// instead of virtual hosted style(bucket.endpoint/key)
+	S3PathStyle = "S3_PATH_STYLE"
+
+	// S3TLSEnabled enables HTTPS while connecting to the endpoint
+	S3TLSEnabled = "S3_TLS_ENABLED"
+
+	// S3TLSCAFile defines the path of CA certificate to verify the endpoint
+	S3TLSCAFile = "S3_TLS_CA_FILE"
+
+	// S3SSEType defines the server side encryption applied on objects.
+	// Supported values are AES256 and aws:kms
+	S3SSEType = "S3_SSE_TYPE"
+
+	// S3SSEKMSKeyID defines the KMS key used when S3_SSE_TYPE is aws:kms
+	S3SSEKMSKeyID = "S3_SSE_KMS_KEY_ID"
+
+	// S3ClusterName defines the first component of object keys
+	S3ClusterName = "S3_CLUSTER_NAME"
+
+	// S3Timeout defines the deadline in seconds of a PUT request
+	S3Timeout = "S3_TIMEOUT"
+
+	// S3ContentType defines the Content-Type of written objects
+	S3ContentType = "S3_CONTENT_TYPE"
+)
+
+const (
+	defaultS3Region      = "us-east-1"
+	defaultS3ClusterName = "default"
+	defaultS3Timeout     = 30 * time.Second
+	defaultS3ContentType = "application/json"
+)
+
+func GetS3Endpoint() string {
+	return strings.TrimSpace(os.Getenv(S3Endpoint))
+}
+
+func GetS3Bucket() string {
+	return strings.TrimSpace(os.Getenv(S3Bucket))
+}
+
+func GetS3Region() string {
+	return getOrDefault(S3Region, defaultS3Region)
+}
+
+func GetS3AccessKeyID() string {
+	return strings.TrimSpace(os.Getenv(S3AccessKeyID))
+}
+
+func GetS3SecretAccessKey() string {
+	return strings.TrimSpace(os.Getenv(S3SecretAccessKey))
+}
+
+func IsS3PathStyleEnabled() bool {
+	return getBool(S3PathStyle)
+}
+
+func IsS3TLSEnabled() bool {
+	return getBool(S3TLSEnabled)
+}
+
+func GetS3TLSCAFile() string {
+	return strings.TrimSpace(os.Getenv(S3TLSCAFile))
+}
+
+func GetS3SSEType() string {
+	return strings.TrimSpace(os.Getenv(S3SSEType))
+}
+
+func GetS3SSEKMSKeyID() string {
+	return strings.TrimSpace(os.Getenv(S3SSEKMSKeyID))
+}
+
+func GetS3ClusterName() string {
+	return getOrDefault(S3ClusterName, defaultS3ClusterName)
+}
+
+func GetS3ContentType() string {
+	return getOrDefault(S3ContentType, defaultS3ContentType)
+}
+
+// GetS3Timeout returns the deadline of PUT request. If missing
+// or invalid then defaults to 30 seconds
+func GetS3Timeout() time.Duration {
+	timeout, err := strconv.Atoi(strings.TrimSpace(os.Getenv(S3Timeout)))
+	if err != nil || timeout <= 0 {
+		return defaultS3Timeout
+	}
+	return time.Duration(timeout) * time.Second
+}
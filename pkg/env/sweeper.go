@@ -0,0 +1,54 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// StuckFinalizerPolicy defines the action taken on volumes whose deletion
+	// is blocked by event finalizer longer than StuckFinalizerThreshold.
+	// Supported values are alert, warn and release. Sweeper is disabled if
+	// it is not set
+	StuckFinalizerPolicy = "STUCK_FINALIZER_POLICY"
+
+	// StuckFinalizerThreshold defines the time in seconds after which
+	// volume blocked by event finalizer is considered as stuck
+	StuckFinalizerThreshold = "STUCK_FINALIZER_THRESHOLD"
+)
+
+const (
+	defaultStuckFinalizerThreshold = time.Hour
+)
+
+func GetStuckFinalizerPolicy() string {
+	return strings.ToLower(strings.TrimSpace(os.Getenv(StuckFinalizerPolicy)))
+}
+
+// GetStuckFinalizerThreshold returns the threshold of stuck volumes.
+// If missing or invalid then defaults to 1 hour
+func GetStuckFinalizerThreshold() time.Duration {
+	threshold, err := strconv.Atoi(strings.TrimSpace(os.Getenv(StuckFinalizerThreshold)))
+	if err != nil || threshold <= 0 {
+		return defaultStuckFinalizerThreshold
+	}
+	return time.Duration(threshold) * time.Second
+}
@@ -0,0 +1,29 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+var (
+	// TenantFields defines comma separated <field>=<source>:<key> mappings of
+	// tenant fields added to volume events ex: team=pvc-label:team. Supported
+	// sources are namespace-label, pvc-label and pvc-annotation. Mappings of
+	// the same field are evaluated in the given order and first match wins
+	TenantFields = "TENANT_FIELDS"
+)
+
+func GetTenantFields() []string {
+	return getList(TenantFields)
+}
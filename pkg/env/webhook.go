@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import "strings"
+
+var (
+	// WebhookBindAddress defines the address on which mutating webhook is served
+	WebhookBindAddress = "WEBHOOK_BIND_ADDRESS"
+
+	// WebhookTLSCertFile defines the path of serving certificate of webhook
+	WebhookTLSCertFile = "WEBHOOK_TLS_CERT_FILE"
+
+	// WebhookTLSKeyFile defines the path of serving key of webhook
+	WebhookTLSKeyFile = "WEBHOOK_TLS_KEY_FILE"
+
+	// WebhookStorageClasses defines comma separated list of NFS StorageClasses
+	// whose volumes are mutated. Volumes of all StorageClasses are mutated if
+	// it is empty
+	WebhookStorageClasses = "WEBHOOK_STORAGE_CLASSES"
+
+	// WebhookCASTypes defines comma separated list of CAS types whose
+	// volumes are mutated
+	WebhookCASTypes = "WEBHOOK_CAS_TYPES"
+
+	// WebhookNFSServerNamespaces defines comma separated list of namespaces
+	// of NFS server resources. Defaults to NFS server namespace
+	WebhookNFSServerNamespaces = "WEBHOOK_NFS_SERVER_NAMESPACES"
+)
+
+const (
+	defaultWebhookBindAddress = ":8443"
+	defaultWebhookTLSCertFile = "/etc/webhook/certs/tls.crt"
+	defaultWebhookTLSKeyFile  = "/etc/webhook/certs/tls.key"
+	defaultWebhookCASType     = "nfs-kernel"
+)
+
+func GetWebhookBindAddress() string {
+	return getOrDefault(WebhookBindAddress, defaultWebhookBindAddress)
+}
+
+func GetWebhookTLSCertFile() string {
+	return getOrDefault(WebhookTLSCertFile, defaultWebhookTLSCertFile)
+}
+
+func GetWebhookTLSKeyFile() string {
+	return getOrDefault(WebhookTLSKeyFile, defaultWebhookTLSKeyFile)
+}
+
+func GetWebhookStorageClasses() []string {
+	return getList(WebhookStorageClasses)
+}
+
+// GetWebhookCASTypes returns the CAS types of volumes to be
+// mutated. If missing then defaults to nfs-kernel
+func GetWebhookCASTypes() []string {
+	if casTypes := getList(WebhookCASTypes); len(casTypes) != 0 {
+		return casTypes
+	}
+	return []string{defaultWebhookCASType}
+}
+
+// GetWebhookNFSServerNamespaces returns the namespaces of NFS
+// server resources. If missing then defaults to NFS server namespace
+func GetWebhookNFSServerNamespaces() []string {
+	if namespaces := getList(WebhookNFSServerNamespaces); len(namespaces) != 0 {
+		return namespaces
+	}
+	if namespace := strings.TrimSpace(GetNFSServerNamespace()); namespace != "" {
+		return []string{namespace}
+	}
+	return nil
+}
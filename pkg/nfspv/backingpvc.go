@@ -0,0 +1,137 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfspv
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// NFSPVCNamespaceLabelKey, NFSPVCNameLabelKey & NFSPVCUIDLabelKey are
+	// the labels set by NFS provisioner on backing PVC. They refer the
+	// NFS PVC for which backing PVC is created
+	NFSPVCNamespaceLabelKey = "nfs.openebs.io/nfs-pvc-namespace"
+	NFSPVCNameLabelKey      = "nfs.openebs.io/nfs-pvc-name"
+	NFSPVCUIDLabelKey       = "nfs.openebs.io/nfs-pvc-uid"
+)
+
+// getBackingPVCRef returns the namespace and name of backing PVC of NFS
+// volume. Backing PVC is discovered in the following order:
+//   - Annotations and then labels of NFS PV whose keys are configured via
+//     NFS_BACKING_PVC_NAMESPACE_KEY and NFS_BACKING_PVC_NAME_KEY.
+//   - Labels set by NFS provisioner on backing PVC which refer the NFS PVC.
+//     Backing PVCs of all namespaces are matched, hence provisioners with
+//     different NFS server namespaces are supported.
+//   - PVC named "nfs-"+<nfs-pv-name>, or the overridden name, in NFS server
+//     namespace of exporter and then in any other namespace.
+//
+// Error is returned if more than one backing PVC matches. If backing PVC
+// is not found then NFS server namespace of exporter is returned
+func (n *nfsVolume) getBackingPVCRef() (string, string, error) {
+	name := getAnnotationOrLabel(n.pvObj.ObjectMeta, n.backingPVCNameKey)
+	namespace := getAnnotationOrLabel(n.pvObj.ObjectMeta, n.backingPVCNamespaceKey)
+	if name == "" {
+		if namespace == "" {
+			labeledNamespace, labeledName, err := n.getBackingPVCRefFromLabels()
+			if err != nil || labeledName != "" {
+				return labeledNamespace, labeledName, err
+			}
+		}
+		// NOTE: We are naming backend PVC with "nfs-"+nfs-pv name
+		name = "nfs-" + n.pvObj.Name
+	}
+	if namespace != "" {
+		return namespace, name, nil
+	}
+
+	_, err := n.pvcLister.PersistentVolumeClaims(n.nfsServerNamespace).Get(name)
+	if err == nil {
+		return n.nfsServerNamespace, name, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return "", "", errors.Wrapf(err, "failed to get backing PVC {%s/%s}", n.nfsServerNamespace, name)
+	}
+	pvcList, err := n.pvcLister.List(labels.Everything())
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to list PVCs to discover backing PVC %s", name)
+	}
+	var namespaces []string
+	for _, pvcObj := range pvcList {
+		if pvcObj.Name == name {
+			namespaces = append(namespaces, pvcObj.Namespace)
+		}
+	}
+	switch len(namespaces) {
+	case 0:
+		return n.nfsServerNamespace, name, nil
+	case 1:
+		return namespaces[0], name, nil
+	}
+	sort.Strings(namespaces)
+	return "", "", errors.Errorf("backing PVC %s of volume %s exists in multiple namespaces %v", name, n.pvObj.Name, namespaces)
+}
+
+// getBackingPVCRefFromLabels returns the backing PVC labeled by NFS
+// provisioner with the NFS PVC of volume. It returns empty if volume
+// is not claimed or backing PVC is not labeled
+func (n *nfsVolume) getBackingPVCRefFromLabels() (string, string, error) {
+	claimRef := n.pvObj.Spec.ClaimRef
+	if claimRef == nil {
+		return "", "", nil
+	}
+	selector := labels.SelectorFromSet(labels.Set{
+		NFSPVCNamespaceLabelKey: claimRef.Namespace,
+		NFSPVCNameLabelKey:      claimRef.Name,
+	})
+	pvcList, err := n.pvcLister.List(selector)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to list backing PVCs of volume %s", n.pvObj.Name)
+	}
+	var refs []string
+	var namespace, name string
+	for _, pvcObj := range pvcList {
+		// NFS PVC might have been recreated with same name
+		if uid := pvcObj.Labels[NFSPVCUIDLabelKey]; uid != "" && claimRef.UID != "" && uid != string(claimRef.UID) {
+			continue
+		}
+		namespace, name = pvcObj.Namespace, pvcObj.Name
+		refs = append(refs, namespace+"/"+name)
+	}
+	if len(refs) > 1 {
+		sort.Strings(refs)
+		return "", "", errors.Errorf("multiple backing PVCs %v are labeled with NFS PVC %s/%s of volume %s",
+			refs, claimRef.Namespace, claimRef.Name, n.pvObj.Name)
+	}
+	return namespace, name, nil
+}
+
+// getAnnotationOrLabel returns the value of given key from annotations
+// and then from labels. It returns empty if key is not set
+func getAnnotationOrLabel(objectMeta metav1.ObjectMeta, key string) string {
+	if key == "" {
+		return ""
+	}
+	if value := objectMeta.Annotations[key]; value != "" {
+		return value
+	}
+	return objectMeta.Labels[key]
+}
@@ -0,0 +1,191 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfspv
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetBackingPVCRef(t *testing.T) {
+	f := newFixture()
+	// Two NFS provisioners with different NFS server namespaces
+	for _, pvcObj := range []*corev1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nfs-pv1",
+				Namespace: "nfs-servers-a",
+				Labels: map[string]string{
+					NFSPVCNamespaceLabelKey: "app-a",
+					NFSPVCNameLabelKey:      "claim1",
+					NFSPVCUIDLabelKey:       "uid-1",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nfs-pv2",
+				Namespace: "nfs-servers-b",
+				Labels: map[string]string{
+					NFSPVCNamespaceLabelKey: "app-b",
+					NFSPVCNameLabelKey:      "claim2",
+					NFSPVCUIDLabelKey:       "uid-2",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nfs-pv5",
+				Namespace: "nfs-servers-a",
+				Labels: map[string]string{
+					NFSPVCNamespaceLabelKey: "app-a",
+					NFSPVCNameLabelKey:      "claim5",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nfs-pv5",
+				Namespace: "nfs-servers-b",
+				Labels: map[string]string{
+					NFSPVCNamespaceLabelKey: "app-a",
+					NFSPVCNameLabelKey:      "claim5",
+				},
+			},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "nfs-pv3", Namespace: "openebs"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "nfs-pv4", Namespace: "nfs-servers-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "nfs-pv6", Namespace: "nfs-servers-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "nfs-pv6", Namespace: "nfs-servers-b"}},
+	} {
+		if err := f.createFakePVC(pvcObj); err != nil {
+			t.Fatalf("failed to create PVC %s/%s: %v", pvcObj.Namespace, pvcObj.Name, err)
+		}
+	}
+
+	tests := map[string]struct {
+		pvObj             *corev1.PersistentVolume
+		expectedNamespace string
+		expectedName      string
+		isErrExpected     bool
+	}{
+		"when backing PVC is labeled in first NFS server namespace": {
+			pvObj:             newClaimedPV("pv1", "app-a", "claim1", "uid-1"),
+			expectedNamespace: "nfs-servers-a",
+			expectedName:      "nfs-pv1",
+		},
+		"when backing PVC is labeled in second NFS server namespace": {
+			pvObj:             newClaimedPV("pv2", "app-b", "claim2", "uid-2"),
+			expectedNamespace: "nfs-servers-b",
+			expectedName:      "nfs-pv2",
+		},
+		"when labeled backing PVC belongs to recreated NFS PVC": {
+			pvObj:             newClaimedPV("pv7", "app-a", "claim1", "uid-7"),
+			expectedNamespace: "openebs",
+			expectedName:      "nfs-pv7",
+		},
+		"when multiple backing PVCs are labeled with NFS PVC": {
+			pvObj:         newClaimedPV("pv5", "app-a", "claim5", ""),
+			isErrExpected: true,
+		},
+		"when PV is annotated with backing PVC": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv1",
+					Annotations: map[string]string{
+						"nfs.openebs.io/backing-pvc-namespace": "nfs-servers-c",
+						"nfs.openebs.io/backing-pvc-name":      "backing-pv1",
+					},
+				},
+				Spec: newClaimedPV("pv1", "app-a", "claim1", "uid-1").Spec,
+			},
+			expectedNamespace: "nfs-servers-c",
+			expectedName:      "backing-pv1",
+		},
+		"when PV is labeled with backing PVC namespace": {
+			pvObj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv6",
+					Labels: map[string]string{
+						"nfs.openebs.io/backing-pvc-namespace": "nfs-servers-b",
+					},
+				},
+			},
+			expectedNamespace: "nfs-servers-b",
+			expectedName:      "nfs-pv6",
+		},
+		"when unlabeled backing PVC exists in NFS server namespace": {
+			pvObj:             &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv3"}},
+			expectedNamespace: "openebs",
+			expectedName:      "nfs-pv3",
+		},
+		"when unlabeled backing PVC exists in other namespace": {
+			pvObj:             &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv4"}},
+			expectedNamespace: "nfs-servers-b",
+			expectedName:      "nfs-pv4",
+		},
+		"when unlabeled backing PVC exists in multiple namespaces": {
+			pvObj:         &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv6"}},
+			isErrExpected: true,
+		},
+		"when backing PVC doesn't exist": {
+			pvObj:             &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv8"}},
+			expectedNamespace: "openebs",
+			expectedName:      "nfs-pv8",
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			nfsVolume := &nfsVolume{
+				pvcLister:              f.pvcInformer.Lister(),
+				pvObj:                  test.pvObj,
+				nfsServerNamespace:     "openebs",
+				backingPVCNamespaceKey: "nfs.openebs.io/backing-pvc-namespace",
+				backingPVCNameKey:      "nfs.openebs.io/backing-pvc-name",
+			}
+			namespace, pvcName, err := nfsVolume.getBackingPVCRef()
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if namespace != test.expectedNamespace || pvcName != test.expectedName {
+				t.Errorf("%q test failed expected backing PVC %s/%s but got %s/%s",
+					name, test.expectedNamespace, test.expectedName, namespace, pvcName)
+			}
+		})
+	}
+}
+
+func newClaimedPV(name, claimNamespace, claimName, claimUID string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			ClaimRef: &corev1.ObjectReference{
+				Namespace: claimNamespace,
+				Name:      claimName,
+				UID:       types.UID(claimUID),
+			},
+		},
+	}
+}
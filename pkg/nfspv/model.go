@@ -17,17 +17,38 @@ limitations under the License.
 package nfspv
 
 import (
+	"encoding/json"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/enrichment"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // NFSCreateVolumeData holds create volume information to send to server
 type NFSCreateVolumeData struct {
 	VolumeProvisioned *NFSVolumeData `json:"volume_provisioned"`
+	// WorkloadContext holds the consumers of NFS PVC, it is
+	// nil if workload enrichment is disabled or PVC doesn't exist
+	WorkloadContext *enrichment.WorkloadContext `json:"workload_context,omitempty"`
+	// Tenant holds the tenant fields of NFS PVC, it is
+	// nil if tenant enrichment is disabled
+	Tenant map[string]string `json:"tenant,omitempty"`
+	// Origin is synthetic/backfilled if volume was provisioned
+	// before volume events are enabled
+	Origin string `json:"origin,omitempty"`
 }
 
 // NFSDeleteVolumeData holds delete volume information to send to server
 type NFSDeleteVolumeData struct {
 	VolumeDeleted *NFSVolumeData `json:"volume_deleted"`
+	// VolumeUsage is nil if deletion time of volume is not known
+	VolumeUsage *VolumeUsage `json:"volume_usage,omitempty"`
+	// Tenant holds the tenant fields of NFS PVC, it is
+	// nil if tenant enrichment is disabled
+	Tenant map[string]string `json:"tenant,omitempty"`
 }
 
 // NFSVolumeData holds the information about NFS & corresponding backend volumes
@@ -36,4 +57,63 @@ type NFSVolumeData struct {
 	NFSPV      *corev1.PersistentVolume      `json:"nfs_pv"`
 	BackingPVC *corev1.PersistentVolumeClaim `json:"backing_pvc"`
 	BackingPV  *corev1.PersistentVolume      `json:"backing_pv"`
+
+	// NFSServerDeployment and NFSServerService are the NFS server
+	// resources which served the volume. They are nil if resources
+	// doesn't exist
+	NFSServerDeployment *appsv1.Deployment `json:"nfs_server_deployment"`
+	NFSServerService    *corev1.Service    `json:"nfs_server_service"`
+
+	// NFSStorageClass and BackingStorageClass are the snapshots of StorageClasses
+	// of NFS PV and backing PV. They are nil if StorageClass doesn't exist
+	NFSStorageClass     *enrichment.StorageClassSnapshot `json:"nfs_storage_class,omitempty"`
+	BackingStorageClass *enrichment.StorageClassSnapshot `json:"backing_storage_class,omitempty"`
+}
+
+// UnmarshalNFSVolumeData returns the NFS volume data and tenant fields
+// from the collected data of given event type. Error is returned if
+// data doesn't hold NFS PV
+func UnmarshalNFSVolumeData(eventType collectorinterface.EventType, data string) (*NFSVolumeData, map[string]string, error) {
+	var volumeData *NFSVolumeData
+	var tenant map[string]string
+	switch eventType {
+	case collectorinterface.CreateEventType:
+		createData := &NFSCreateVolumeData{}
+		if err := json.Unmarshal([]byte(data), createData); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to unmarshal NFS create volume data")
+		}
+		volumeData, tenant = createData.VolumeProvisioned, createData.Tenant
+	case collectorinterface.DeleteEventType:
+		deleteData := &NFSDeleteVolumeData{}
+		if err := json.Unmarshal([]byte(data), deleteData); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to unmarshal NFS delete volume data")
+		}
+		volumeData, tenant = deleteData.VolumeDeleted, deleteData.Tenant
+	default:
+		return nil, nil, errors.Errorf("unsupported event type %q", eventType)
+	}
+	if volumeData == nil || volumeData.NFSPV == nil {
+		return nil, nil, errors.Errorf("NFS %s volume data is empty", eventType)
+	}
+	return volumeData, tenant, nil
+}
+
+// storageClassSnapshots are persisted in NFS PV annotation once
+// create event is sent
+type storageClassSnapshots struct {
+	NFSStorageClass     *enrichment.StorageClassSnapshot `json:"nfs_storage_class,omitempty"`
+	BackingStorageClass *enrichment.StorageClassSnapshot `json:"backing_storage_class,omitempty"`
+}
+
+// VolumeUsage holds the lifetime and usage duration of deleted volume
+type VolumeUsage struct {
+	// ProvisionedAt is the creation time of the earliest of NFS PV & backing PV
+	ProvisionedAt metav1.Time `json:"provisioned_at"`
+	// DeletedAt is the deletion time of NFS PV
+	DeletedAt       metav1.Time `json:"deleted_at"`
+	LifetimeSeconds int64       `json:"lifetime_seconds"`
+	// CapacityGiBHours is the capacity of backing PV(or NFS PV if backing PV
+	// capacity is unknown) in GiB multiplied by lifetime in hours rounded
+	// to 6 decimal places
+	CapacityGiBHours float64 `json:"capacity_gib_hours"`
 }
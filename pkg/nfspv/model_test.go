@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfspv
+
+import (
+	"testing"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+)
+
+func TestUnmarshalNFSVolumeData(t *testing.T) {
+	tests := map[string]struct {
+		eventType      collectorinterface.EventType
+		data           string
+		expectedPVName string
+		expectedTenant string
+		isErrExpected  bool
+	}{
+		"when create event data is unmarshalled": {
+			eventType:      collectorinterface.CreateEventType,
+			data:           `{"volume_provisioned":{"nfs_pv":{"metadata":{"name":"pv1"}}},"tenant":{"team":"storage"}}`,
+			expectedPVName: "pv1",
+			expectedTenant: "storage",
+		},
+		"when delete event data is unmarshalled": {
+			eventType:      collectorinterface.DeleteEventType,
+			data:           `{"volume_deleted":{"nfs_pv":{"metadata":{"name":"pv2"}}}}`,
+			expectedPVName: "pv2",
+		},
+		"when data doesn't hold NFS PV": {
+			eventType:     collectorinterface.CreateEventType,
+			data:          `{"volume_provisioned":{"nfs_pvc":{}}}`,
+			isErrExpected: true,
+		},
+		"when data of other event type is unmarshalled": {
+			eventType:     collectorinterface.DeleteEventType,
+			data:          `{"volume_provisioned":{"nfs_pv":{"metadata":{"name":"pv3"}}}}`,
+			isErrExpected: true,
+		},
+		"when data is not valid JSON": {
+			eventType:     collectorinterface.CreateEventType,
+			data:          `{"volume_provisioned":`,
+			isErrExpected: true,
+		},
+		"when event type is not supported": {
+			eventType:     collectorinterface.EventType("update"),
+			data:          `{}`,
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			volumeData, tenant, err := UnmarshalNFSVolumeData(test.eventType, test.data)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if test.isErrExpected {
+				return
+			}
+			if volumeData.NFSPV.Name != test.expectedPVName {
+				t.Errorf("%q test failed expected NFS PV %s but got %s", name, test.expectedPVName, volumeData.NFSPV.Name)
+			}
+			if tenant["team"] != test.expectedTenant {
+				t.Errorf("%q test failed expected tenant %q but got %v", name, test.expectedTenant, tenant)
+			}
+		})
+	}
+}
@@ -19,17 +19,22 @@ package nfspv
 import (
 	"context"
 	"encoding/json"
+	"math"
 
 	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/enrichment"
 	"github.com/mayadata-io/volume-events-exporter/pkg/env"
 	"github.com/mayadata-io/volume-events-exporter/pkg/helper"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
 )
 
 const (
@@ -49,12 +54,22 @@ type nfsVolume struct {
 
 	pvLister corev1listers.PersistentVolumeLister
 	pvObj    *corev1.PersistentVolume
-	// nfsServerNamespace states the namespace of NFSServer deployment
+	// nfsServerNamespace states the namespace of NFSServer deployment. It
+	// is used when backing PVC can't be discovered from other namespaces
 	nfsServerNamespace string
-	annotationPrefix   string
+	// backingPVCNamespaceKey & backingPVCNameKey are the optional annotation
+	// or label keys on NFS PV which overrides the backing PVC reference
+	backingPVCNamespaceKey string
+	backingPVCNameKey      string
+	annotationPrefix       string
 	// dataType represents the type of the data that server
 	// can understand. As of now JSON is supported
 	dataType collectorinterface.DataType
+	// resolvers enrich the volume events with additional context
+	resolvers enrichment.Resolvers
+	// storageClasses holds the StorageClass snapshots of last
+	// collected event, they are persisted on annotating create event
+	storageClasses *storageClassSnapshots
 }
 
 func NewNFSVolume(
@@ -62,15 +77,19 @@ func NewNFSVolume(
 	pvcLister corev1listers.PersistentVolumeClaimLister,
 	pvLister corev1listers.PersistentVolumeLister,
 	pvObj *corev1.PersistentVolume,
-	dataType collectorinterface.DataType) collectorinterface.VolumeEventCollector {
+	dataType collectorinterface.DataType,
+	resolvers enrichment.Resolvers) collectorinterface.VolumeEventCollector {
 	return &nfsVolume{
-		clientset:          clientset,
-		pvcLister:          pvcLister,
-		pvLister:           pvLister,
-		pvObj:              pvObj,
-		nfsServerNamespace: env.GetNFSServerNamespace(),
-		annotationPrefix:   "nfs.",
-		dataType:           dataType,
+		clientset:              clientset,
+		pvcLister:              pvcLister,
+		pvLister:               pvLister,
+		pvObj:                  pvObj,
+		nfsServerNamespace:     env.GetNFSServerNamespace(),
+		backingPVCNamespaceKey: env.GetNFSBackingPVCNamespaceKey(),
+		backingPVCNameKey:      env.GetNFSBackingPVCNameKey(),
+		annotationPrefix:       "nfs.",
+		dataType:               dataType,
+		resolvers:              resolvers,
 	}
 }
 
@@ -106,8 +125,29 @@ func (n *nfsVolume) CollectCreateEvents() (string, error) {
 	volumeData.BackingPV.DeletionTimestamp = nil
 	volumeData.BackingPV.DeletionGracePeriodSeconds = nil
 
+	if volumeData.NFSServerDeployment != nil {
+		volumeData.NFSServerDeployment.DeletionTimestamp = nil
+		volumeData.NFSServerDeployment.DeletionGracePeriodSeconds = nil
+	}
+
+	if volumeData.NFSServerService != nil {
+		volumeData.NFSServerService.DeletionTimestamp = nil
+		volumeData.NFSServerService.DeletionGracePeriodSeconds = nil
+	}
+
 	createData := &NFSCreateVolumeData{
 		VolumeProvisioned: volumeData,
+		Origin:            n.pvObj.Annotations[collectorinterface.VolumeEventOriginAnnotation],
+	}
+	if n.resolvers.Workload != nil && volumeData.NFSPVC != nil {
+		createData.WorkloadContext, err = n.resolvers.Workload.Resolve(volumeData.NFSPVC.Namespace, volumeData.NFSPVC.Name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to resolve workload context of PVC %s/%s", volumeData.NFSPVC.Namespace, volumeData.NFSPVC.Name)
+		}
+	}
+	createData.Tenant, err = n.resolveTenant(volumeData)
+	if err != nil {
+		return "", err
 	}
 	rawData, err := json.Marshal(createData)
 	if err != nil {
@@ -126,10 +166,15 @@ func (n *nfsVolume) CollectDeleteEvents() (string, error) {
 		return "", err
 	}
 
-	createData := &NFSDeleteVolumeData{
+	deleteData := &NFSDeleteVolumeData{
 		VolumeDeleted: volumeData,
+		VolumeUsage:   getVolumeUsage(volumeData.NFSPV, volumeData.BackingPV),
 	}
-	rawData, err := json.Marshal(createData)
+	deleteData.Tenant, err = n.resolveTenant(volumeData)
+	if err != nil {
+		return "", err
+	}
+	rawData, err := json.Marshal(deleteData)
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to marshal delete volume events")
 	}
@@ -142,6 +187,14 @@ func (n *nfsVolume) AnnotateCreateEvent(pvObj *corev1.PersistentVolume) (*corev1
 	}
 	annoKey := n.annotationPrefix + collectorinterface.VolumeCreateEventAnnotation
 	pvObj.Annotations[annoKey] = collectorinterface.OpenebsEventSentAnnotationValue
+	snapshotValue, err := n.getStorageClassSnapshotAnnotation()
+	if err != nil {
+		// Delete event falls back to StorageClasses which exist at that time
+		klog.Warningf("Failed to capture StorageClass snapshots of volume %s: %v", pvObj.Name, err)
+	}
+	if snapshotValue != "" {
+		pvObj.Annotations[n.annotationPrefix+collectorinterface.StorageClassSnapshotAnnotation] = snapshotValue
+	}
 	return n.clientset.CoreV1().PersistentVolumes().Update(context.TODO(), pvObj, metav1.UpdateOptions{})
 }
 
@@ -174,10 +227,120 @@ func (n *nfsVolume) GetDataType() collectorinterface.DataType {
 	return n.dataType
 }
 
+// AddEventFinalizer adds event finalizer on NFS PV, backing PVC, backing
+// PV and NFS server resources using patch so that volume is not deleted
+// till delete event is sent, even if provisioner is not configured to add them
+func (n *nfsVolume) AddEventFinalizer(pvObj *corev1.PersistentVolume) (*corev1.PersistentVolume, error) {
+	openebsEventFinalizer := n.annotationPrefix + collectorinterface.VolumeEventsFinalizer
+	patchBytes, err := getAddFinalizerPatch(openebsEventFinalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step1: Add finalizer on NFS PV(at first) so that NFS PV is retained
+	// even if adding finalizer on backing resources fails
+	if !isFinalizerRequired(pvObj.ObjectMeta, openebsEventFinalizer) {
+		return pvObj, n.addFinalizerOnBackingResources(patchBytes, openebsEventFinalizer)
+	}
+	newPVObj, err := n.clientset.CoreV1().
+		PersistentVolumes().
+		Patch(context.TODO(), pvObj.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to add %s finalizer on PV %s", openebsEventFinalizer, pvObj.Name)
+	}
+	// Updating inmemory reference is required to avoid update conflicts
+	// while annotating volume with event information
+	n.pvObj = newPVObj
+	return newPVObj, n.addFinalizerOnBackingResources(patchBytes, openebsEventFinalizer)
+}
+
+// addFinalizerOnBackingResources adds event finalizer on backing PVC,
+// then on backing PV and at last on NFS server deployment & service
+// (Step2, Step3 & Step4)
+func (n *nfsVolume) addFinalizerOnBackingResources(patchBytes []byte, finalizer string) error {
+	backendPVCNamespace, backendPVCName, err := n.getBackingPVCRef()
+	if err != nil {
+		return err
+	}
+	backendPVC, err := n.getPVCCopy(backendPVCNamespace, backendPVCName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get backing PVC {%s/%s}", backendPVCNamespace, backendPVCName)
+	}
+	if isFinalizerRequired(backendPVC.ObjectMeta, finalizer) {
+		_, err = n.clientset.CoreV1().
+			PersistentVolumeClaims(backendPVC.Namespace).
+			Patch(context.TODO(), backendPVC.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to add %s finalizer on PVC %s/%s", finalizer, backendPVC.Namespace, backendPVC.Name)
+		}
+	}
+
+	if backendPVC.Spec.VolumeName == "" {
+		return errors.Errorf("backing PVC {%s/%s} is not yet bound", backendPVC.Namespace, backendPVC.Name)
+	}
+	backendPV, err := n.getPVCopy(backendPVC.Spec.VolumeName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get backing PV %s", backendPVC.Spec.VolumeName)
+	}
+	if isFinalizerRequired(backendPV.ObjectMeta, finalizer) {
+		_, err = n.clientset.CoreV1().
+			PersistentVolumes().
+			Patch(context.TODO(), backendPV.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to add %s finalizer on PV %s", finalizer, backendPV.Name)
+		}
+	}
+	return n.addFinalizerOnNFSServer(backendPVC.Namespace, patchBytes, finalizer)
+}
+
+// addFinalizerOnNFSServer adds event finalizer on NFS server deployment
+// and service of volume. Server resources which doesn't exist are skipped
+func (n *nfsVolume) addFinalizerOnNFSServer(namespace string, patchBytes []byte, finalizer string) error {
+	nfsServerDeployment, err := n.getNFSServerDeployment(namespace)
+	if err != nil {
+		return err
+	}
+	if nfsServerDeployment != nil && isFinalizerRequired(nfsServerDeployment.ObjectMeta, finalizer) {
+		_, err = n.clientset.AppsV1().
+			Deployments(namespace).
+			Patch(context.TODO(), nfsServerDeployment.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to add %s finalizer on deployment %s/%s", finalizer, namespace, nfsServerDeployment.Name)
+		}
+	}
+
+	nfsServerService, err := n.getNFSServerService(namespace)
+	if err != nil {
+		return err
+	}
+	if nfsServerService != nil && isFinalizerRequired(nfsServerService.ObjectMeta, finalizer) {
+		_, err = n.clientset.CoreV1().
+			Services(namespace).
+			Patch(context.TODO(), nfsServerService.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to add %s finalizer on service %s/%s", finalizer, namespace, nfsServerService.Name)
+		}
+	}
+	return nil
+}
+
 func (n *nfsVolume) RemoveEventFinalizer() error {
 	openebsEventFinalizer := n.annotationPrefix + collectorinterface.VolumeEventsFinalizer
 
-	backendPVC, err := n.getPVCCopy(n.nfsServerNamespace, "nfs-"+n.pvObj.Name)
+	backendPVCNamespace, backendPVCName, err := n.getBackingPVCRef()
+	if err != nil {
+		return err
+	}
+
+	// Step0: Remove finalizer on NFS server deployment & service. They are
+	// retained till delete event is sent so that event can describe the server.
+	// Failures are only logged, server resources must not hold back removal
+	// of finalizer on volume resources
+	if err := n.removeFinalizerOnNFSServer(backendPVCNamespace, openebsEventFinalizer); err != nil {
+		klog.Errorf("Failed to remove %s finalizer on NFS server resources of PV %s: %v", openebsEventFinalizer, n.pvObj.Name, err)
+	}
+
+	backendPVC, err := n.getPVCCopy(backendPVCNamespace, backendPVCName)
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return err
 	}
@@ -208,6 +371,30 @@ func (n *nfsVolume) RemoveEventFinalizer() error {
 	return err
 }
 
+// removeFinalizerOnNFSServer removes event finalizer on NFS server
+// deployment and service of volume. Resources which doesn't exist are
+// treated as done
+func (n *nfsVolume) removeFinalizerOnNFSServer(namespace, finalizer string) error {
+	var errs []error
+	nfsServerDeployment, err := n.getNFSServerDeployment(namespace)
+	if err != nil {
+		errs = append(errs, err)
+	} else if nfsServerDeployment != nil {
+		if err := n.removeFinalizerOnDeployment(nfsServerDeployment, finalizer); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	nfsServerService, err := n.getNFSServerService(namespace)
+	if err != nil {
+		errs = append(errs, err)
+	} else if nfsServerService != nil {
+		if err := n.removeFinalizerOnService(nfsServerService, finalizer); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
 func (n *nfsVolume) removeFinalizerOnPVC(pvcObj *corev1.PersistentVolumeClaim, finalizer string) error {
 	isFinalizerRemoved := helper.RemoveFinalizer(&pvcObj.ObjectMeta, finalizer)
 	if !isFinalizerRemoved {
@@ -238,6 +425,36 @@ func (n *nfsVolume) removeFinalizerOnPV(pvObj *corev1.PersistentVolume, finalize
 	return nil
 }
 
+func (n *nfsVolume) removeFinalizerOnDeployment(deployObj *appsv1.Deployment, finalizer string) error {
+	isFinalizerRemoved := helper.RemoveFinalizer(&deployObj.ObjectMeta, finalizer)
+	if !isFinalizerRemoved {
+		// If finalizer is not deleted means finalizer doesn't exist so no need take action
+		return nil
+	}
+	_, err := n.clientset.AppsV1().
+		Deployments(deployObj.Namespace).
+		Update(context.TODO(), deployObj, metav1.UpdateOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s finalizer on deployment %s/%s", finalizer, deployObj.Namespace, deployObj.Name)
+	}
+	return nil
+}
+
+func (n *nfsVolume) removeFinalizerOnService(serviceObj *corev1.Service, finalizer string) error {
+	isFinalizerRemoved := helper.RemoveFinalizer(&serviceObj.ObjectMeta, finalizer)
+	if !isFinalizerRemoved {
+		// If finalizer is not deleted means finalizer doesn't exist so no need take action
+		return nil
+	}
+	_, err := n.clientset.CoreV1().
+		Services(serviceObj.Namespace).
+		Update(context.TODO(), serviceObj, metav1.UpdateOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s finalizer on service %s/%s", finalizer, serviceObj.Namespace, serviceObj.Name)
+	}
+	return nil
+}
+
 func (n *nfsVolume) getVolumeData() (*NFSVolumeData, error) {
 	nfsPVC, err := n.getPVCCopy(n.pvObj.Spec.ClaimRef.Namespace, n.pvObj.Spec.ClaimRef.Name)
 	if err != nil && !k8serrors.IsNotFound(err) {
@@ -250,11 +467,13 @@ func (n *nfsVolume) getVolumeData() (*NFSVolumeData, error) {
 		return nil, errors.Wrapf(err, "failed to get PV %s", n.pvObj.Name)
 	}
 
-	// NOTE: We are naming backend PVC with "nfs-"+nfs-pv name
-	backendPVCName := "nfs-" + nfsPV.Name
-	backendPVC, err := n.getPVCCopy(n.nfsServerNamespace, backendPVCName)
+	backendPVCNamespace, backendPVCName, err := n.getBackingPVCRef()
+	if err != nil {
+		return nil, err
+	}
+	backendPVC, err := n.getPVCCopy(backendPVCNamespace, backendPVCName)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get backing PVC {%s/%s}", n.nfsServerNamespace, backendPVCName)
+		return nil, errors.Wrapf(err, "failed to get backing PVC {%s/%s}", backendPVCNamespace, backendPVCName)
 	}
 
 	backendPV, err := n.getPVCopy(backendPVC.Spec.VolumeName)
@@ -262,12 +481,167 @@ func (n *nfsVolume) getVolumeData() (*NFSVolumeData, error) {
 		return nil, errors.Wrapf(err, "failed to get backing PV %s", backendPVC.Spec.VolumeName)
 	}
 
-	return &NFSVolumeData{
-		NFSPVC:     nfsPVC,
-		NFSPV:      nfsPV,
-		BackingPVC: backendPVC,
-		BackingPV:  backendPV,
-	}, nil
+	nfsServerDeployment, err := n.getNFSServerDeployment(backendPVCNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	nfsServerService, err := n.getNFSServerService(backendPVCNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeData := &NFSVolumeData{
+		NFSPVC:              nfsPVC,
+		NFSPV:               nfsPV,
+		BackingPVC:          backendPVC,
+		BackingPV:           backendPV,
+		NFSServerDeployment: nfsServerDeployment,
+		NFSServerService:    nfsServerService,
+	}
+	if n.resolvers.StorageClass != nil {
+		snapshots, err := n.getStorageClassSnapshots(nfsPV, backendPVC, backendPV)
+		if err != nil {
+			return nil, err
+		}
+		volumeData.NFSStorageClass = snapshots.NFSStorageClass
+		volumeData.BackingStorageClass = snapshots.BackingStorageClass
+		n.storageClasses = snapshots
+	}
+	return volumeData, nil
+}
+
+// getStorageClassSnapshots returns the StorageClass snapshots persisted
+// on NFS PV while sending create event. If they are not persisted then
+// StorageClasses are resolved from current state. Persisted annotation
+// is removed from given NFS PV since snapshots are part of volume data
+func (n *nfsVolume) getStorageClassSnapshots(
+	nfsPV *corev1.PersistentVolume,
+	backendPVC *corev1.PersistentVolumeClaim,
+	backendPV *corev1.PersistentVolume) (*storageClassSnapshots, error) {
+	annoKey := n.annotationPrefix + collectorinterface.StorageClassSnapshotAnnotation
+	if value, isExist := nfsPV.Annotations[annoKey]; isExist {
+		delete(nfsPV.Annotations, annoKey)
+		snapshots := &storageClassSnapshots{}
+		if err := json.Unmarshal([]byte(value), snapshots); err == nil {
+			return snapshots, nil
+		}
+		klog.Warningf("Ignoring invalid StorageClass snapshots of volume %s", nfsPV.Name)
+	}
+
+	var err error
+	snapshots := &storageClassSnapshots{}
+	snapshots.NFSStorageClass, err = n.resolvers.StorageClass.Resolve(nfsPV.Spec.StorageClassName)
+	if err != nil {
+		return nil, err
+	}
+	backingStorageClassName := backendPV.Spec.StorageClassName
+	if backingStorageClassName == "" && backendPVC.Spec.StorageClassName != nil {
+		backingStorageClassName = *backendPVC.Spec.StorageClassName
+	}
+	snapshots.BackingStorageClass, err = n.resolvers.StorageClass.Resolve(backingStorageClassName)
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// getStorageClassSnapshotAnnotation returns the value of annotation which
+// persists the StorageClass snapshots of the volume. Snapshots are resolved
+// if create event data is not collected(ex: delivered in an earlier attempt).
+// It returns empty value if snapshots are not available
+func (n *nfsVolume) getStorageClassSnapshotAnnotation() (string, error) {
+	if n.resolvers.StorageClass == nil {
+		return "", nil
+	}
+	if n.storageClasses == nil {
+		if _, err := n.getVolumeData(); err != nil {
+			return "", err
+		}
+	}
+	if n.storageClasses.NFSStorageClass == nil && n.storageClasses.BackingStorageClass == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(n.storageClasses)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal StorageClass snapshots")
+	}
+	return string(value), nil
+}
+
+// resolveTenant returns the tenant fields of NFS PVC. Namespace is taken
+// from claim reference since NFS PVC might not exist during deletion
+func (n *nfsVolume) resolveTenant(volumeData *NFSVolumeData) (map[string]string, error) {
+	if n.resolvers.Tenant == nil || volumeData.NFSPV.Spec.ClaimRef == nil {
+		return nil, nil
+	}
+	namespace := volumeData.NFSPV.Spec.ClaimRef.Namespace
+	tenant, err := n.resolvers.Tenant.Resolve(namespace, volumeData.NFSPVC)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve tenant of PV %s", volumeData.NFSPV.Name)
+	}
+	return tenant, nil
+}
+
+// getAddFinalizerPatch returns the strategic merge patch which adds given
+// finalizer. Finalizers are merged by API server so that finalizers
+// added by others in the meantime are retained
+func getAddFinalizerPatch(finalizer string) ([]byte, error) {
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers": []string{finalizer},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build patch of %s finalizer", finalizer)
+	}
+	return patchBytes, nil
+}
+
+// isFinalizerRequired returns true if object doesn't have given finalizer.
+// Finalizers can't be added once object is marked for deletion
+func isFinalizerRequired(objectMeta metav1.ObjectMeta, finalizer string) bool {
+	if objectMeta.DeletionTimestamp != nil {
+		return false
+	}
+	for _, existingFinalizer := range objectMeta.Finalizers {
+		if existingFinalizer == finalizer {
+			return false
+		}
+	}
+	return true
+}
+
+// getVolumeUsage returns the lifetime & usage of volume derived from
+// NFS PV and backing PV. It returns nil if NFS PV is not yet deleted
+func getVolumeUsage(nfsPV, backingPV *corev1.PersistentVolume) *VolumeUsage {
+	if nfsPV.DeletionTimestamp == nil {
+		return nil
+	}
+	provisionedAt := nfsPV.CreationTimestamp
+	capacity := nfsPV.Spec.Capacity[corev1.ResourceStorage]
+	if backingPV != nil {
+		// Backing PV is provisioned prior to NFS PV and
+		// holds the actual capacity consumed by the volume
+		if !backingPV.CreationTimestamp.IsZero() && backingPV.CreationTimestamp.Before(&provisionedAt) {
+			provisionedAt = backingPV.CreationTimestamp
+		}
+		if backingCapacity, isExist := backingPV.Spec.Capacity[corev1.ResourceStorage]; isExist {
+			capacity = backingCapacity
+		}
+	}
+
+	lifetime := nfsPV.DeletionTimestamp.Sub(provisionedAt.Time)
+	if lifetime < 0 {
+		lifetime = 0
+	}
+	capacityGiB := float64(capacity.Value()) / (1 << 30)
+	return &VolumeUsage{
+		ProvisionedAt:    provisionedAt,
+		DeletedAt:        *nfsPV.DeletionTimestamp,
+		LifetimeSeconds:  int64(lifetime.Seconds()),
+		CapacityGiBHours: math.Round(capacityGiB*lifetime.Hours()*1e6) / 1e6,
+	}
 }
 
 func (n *nfsVolume) getPVCopy(pvName string) (*corev1.PersistentVolume, error) {
@@ -294,6 +668,43 @@ func (n *nfsVolume) getPVCCopy(namespace, name string) (*corev1.PersistentVolume
 	return pvcObj.DeepCopy(), nil
 }
 
+// getNFSServerDeployment returns the NFS server deployment of volume from
+// given namespace. It returns nil if deployment doesn't exist.
+// NOTE: Server resources are fetched from API server since they are
+//		 required only while sending events and caching deployments &
+//		 services of the cluster is costlier
+func (n *nfsVolume) getNFSServerDeployment(namespace string) (*appsv1.Deployment, error) {
+	// NOTE: We are naming NFS server deployment with "nfs-"+nfs-pv name
+	deploymentName := "nfs-" + n.pvObj.Name
+	deployObj, err := n.clientset.AppsV1().
+		Deployments(namespace).
+		Get(context.TODO(), deploymentName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get NFS server deployment %s/%s", namespace, deploymentName)
+	}
+	return deployObj, nil
+}
+
+// getNFSServerService returns the NFS server service of volume from
+// given namespace. It returns nil if service doesn't exist
+func (n *nfsVolume) getNFSServerService(namespace string) (*corev1.Service, error) {
+	// NOTE: We are naming NFS server service with "nfs-"+nfs-pv name
+	serviceName := "nfs-" + n.pvObj.Name
+	serviceObj, err := n.clientset.CoreV1().
+		Services(namespace).
+		Get(context.TODO(), serviceName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get NFS server service %s/%s", namespace, serviceName)
+	}
+	return serviceObj, nil
+}
+
 func (n *nfsVolume) isSupportedDataType() bool {
 	switch n.dataType {
 	case collectorinterface.JSONDataType:
@@ -19,18 +19,27 @@ package nfspv
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/enrichment"
 	"github.com/mayadata-io/volume-events-exporter/pkg/helper"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	kubeinformers "k8s.io/client-go/informers"
 	corev1informer "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 type fixture struct {
@@ -304,6 +313,7 @@ func TestCollectDeleteEvents(t *testing.T) {
 		backendPVC    *corev1.PersistentVolumeClaim
 		backendPV     *corev1.PersistentVolume
 		dataType      collectorinterface.DataType
+		expectedUsage *VolumeUsage
 		isErrExpected bool
 	}{
 		"when all nfs volume resources exist in the system with deletion timestamp": {
@@ -496,6 +506,63 @@ func TestCollectDeleteEvents(t *testing.T) {
 			dataType:      collectorinterface.YAMLDataType,
 			isErrExpected: true,
 		},
+		"when nfs pvc doesn't exist usage is derived from nfs PV and backend PV": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv5",
+					CreationTimestamp: newTime(2021, 10, 1, 10, 5),
+					DeletionTimestamp: newTimePtr(2021, 10, 3, 10, 5),
+					Finalizers: []string{
+						"kubernetes.io/pv-protection",
+						"nfs.events.openebs.io/finalizer",
+					},
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					Capacity: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("4Gi"),
+					},
+					ClaimRef: &corev1.ObjectReference{
+						Name:      "pvc5",
+						Namespace: "ns1",
+					},
+				},
+			},
+			backendPVC: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "nfs-pv5",
+					Namespace:         "openebs",
+					CreationTimestamp: newTime(2021, 10, 1, 10, 0),
+					Finalizers: []string{
+						"nfs.events.openebs.io/finalizer",
+					},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					VolumeName: "backend-pv5",
+				},
+			},
+			backendPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "backend-pv5",
+					CreationTimestamp: newTime(2021, 10, 1, 10, 0),
+					Finalizers: []string{
+						"kubernetes.io/pv-protection",
+						"nfs.events.openebs.io/finalizer",
+					},
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					Capacity: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("5Gi"),
+					},
+				},
+			},
+			dataType: collectorinterface.JSONDataType,
+			expectedUsage: &VolumeUsage{
+				ProvisionedAt:    newTime(2021, 10, 1, 10, 0),
+				DeletedAt:        newTime(2021, 10, 3, 10, 5),
+				LifetimeSeconds:  (48*60 + 5) * 60,
+				CapacityGiBHours: 240.416667,
+			},
+		},
 	}
 	for name, test := range tests {
 		name := name
@@ -543,11 +610,115 @@ func TestCollectDeleteEvents(t *testing.T) {
 				if data.VolumeDeleted.BackingPVC == nil {
 					t.Fatalf("%q test failed expected backend PV should exist", name)
 				}
+				if data.VolumeUsage == nil {
+					t.Fatalf("%q test failed expected volume usage to exist", name)
+				}
+				// Unmarshal of timestamps results in local time
+				data.VolumeUsage.ProvisionedAt = metav1.NewTime(data.VolumeUsage.ProvisionedAt.UTC())
+				data.VolumeUsage.DeletedAt = metav1.NewTime(data.VolumeUsage.DeletedAt.UTC())
+				if test.expectedUsage != nil && !reflect.DeepEqual(*test.expectedUsage, *data.VolumeUsage) {
+					t.Fatalf("%q test failed expected volume usage %+v but got %+v", name, *test.expectedUsage, *data.VolumeUsage)
+				}
 			}
 		})
 	}
 }
 
+func TestGetVolumeUsage(t *testing.T) {
+	tests := map[string]struct {
+		nfsPV         *corev1.PersistentVolume
+		backingPV     *corev1.PersistentVolume
+		expectedUsage *VolumeUsage
+	}{
+		"when nfs PV is not deleted": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv1",
+					CreationTimestamp: newTime(2021, 10, 1, 10, 0),
+				},
+			},
+		},
+		"when backing PV doesn't exist capacity of nfs PV is used": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv2",
+					CreationTimestamp: newTime(2021, 10, 1, 10, 0),
+					DeletionTimestamp: newTimePtr(2021, 10, 1, 12, 30),
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					Capacity: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("512Mi"),
+					},
+				},
+			},
+			expectedUsage: &VolumeUsage{
+				ProvisionedAt:    newTime(2021, 10, 1, 10, 0),
+				DeletedAt:        newTime(2021, 10, 1, 12, 30),
+				LifetimeSeconds:  150 * 60,
+				CapacityGiBHours: 0.5 * 2.5,
+			},
+		},
+		"when backing PV is created after nfs PV": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv3",
+					CreationTimestamp: newTime(2021, 10, 1, 10, 0),
+					DeletionTimestamp: newTimePtr(2021, 10, 1, 11, 0),
+				},
+			},
+			backingPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "backend-pv3",
+					CreationTimestamp: newTime(2021, 10, 1, 10, 1),
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					Capacity: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("2Gi"),
+					},
+				},
+			},
+			expectedUsage: &VolumeUsage{
+				ProvisionedAt:    newTime(2021, 10, 1, 10, 0),
+				DeletedAt:        newTime(2021, 10, 1, 11, 0),
+				LifetimeSeconds:  60 * 60,
+				CapacityGiBHours: 2,
+			},
+		},
+		"when deletion timestamp is prior to creation timestamp": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "pv4",
+					CreationTimestamp: newTime(2021, 10, 1, 10, 0),
+					DeletionTimestamp: newTimePtr(2021, 10, 1, 9, 0),
+				},
+			},
+			expectedUsage: &VolumeUsage{
+				ProvisionedAt: newTime(2021, 10, 1, 10, 0),
+				DeletedAt:     newTime(2021, 10, 1, 9, 0),
+			},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			usage := getVolumeUsage(test.nfsPV, test.backingPV)
+			if !reflect.DeepEqual(test.expectedUsage, usage) {
+				t.Fatalf("%q test failed expected volume usage %+v but got %+v", name, test.expectedUsage, usage)
+			}
+		})
+	}
+}
+
+func newTime(year int, month time.Month, day, hour, min int) metav1.Time {
+	return metav1.NewTime(time.Date(year, month, day, hour, min, 0, 0, time.UTC))
+}
+
+func newTimePtr(year int, month time.Month, day, hour, min int) *metav1.Time {
+	t := newTime(year, month, day, hour, min)
+	return &t
+}
+
 func TestAnnotateCreateEvent(t *testing.T) {
 	f := newFixture()
 	tests := map[string]struct {
@@ -1036,3 +1207,481 @@ func RemoveEventFinalizer(t *testing.T) {
 		})
 	}
 }
+
+func TestNFSServerResources(t *testing.T) {
+	f := newFixture()
+	eventFinalizer := "nfs." + collectorinterface.VolumeEventsFinalizer
+	tests := map[string]struct {
+		nfsPV               *corev1.PersistentVolume
+		nfsServerDeployment *appsv1.Deployment
+		nfsServerService    *corev1.Service
+	}{
+		"when NFS server deployment and service exist": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "pv1",
+					Finalizers: []string{eventFinalizer},
+				},
+			},
+			nfsServerDeployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "nfs-pv1",
+					Namespace:         "openebs",
+					DeletionTimestamp: func() *metav1.Time { t := metav1.Now(); return &t }(),
+					Finalizers:        []string{eventFinalizer},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							NodeSelector: map[string]string{"kubernetes.io/hostname": "node1"},
+							Containers: []corev1.Container{
+								{
+									Name:  "nfs-server",
+									Image: "openebs/nfs-server-alpine:0.7.1",
+									Resources: corev1.ResourceRequirements{
+										Requests: corev1.ResourceList{
+											corev1.ResourceCPU: resource.MustParse("100m"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			nfsServerService: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "nfs-pv1",
+					Namespace:         "openebs",
+					DeletionTimestamp: func() *metav1.Time { t := metav1.Now(); return &t }(),
+					Finalizers:        []string{eventFinalizer},
+				},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: "10.0.0.10",
+				},
+			},
+		},
+		"when NFS server deployment and service doesn't exist": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "pv2",
+					Finalizers: []string{eventFinalizer},
+				},
+			},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			err := f.createFakePV(test.nfsPV)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur while creating PV but got %v", name, err)
+			}
+			if test.nfsServerDeployment != nil {
+				_, err := f.clientset.AppsV1().Deployments("openebs").Create(context.TODO(), test.nfsServerDeployment, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatalf("%q test failed expected error not to occur while creating deployment but got %v", name, err)
+				}
+			}
+			if test.nfsServerService != nil {
+				_, err := f.clientset.CoreV1().Services("openebs").Create(context.TODO(), test.nfsServerService, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatalf("%q test failed expected error not to occur while creating service but got %v", name, err)
+				}
+			}
+			nfsVolume := &nfsVolume{
+				clientset:          f.clientset,
+				pvcLister:          f.pvcInformer.Lister(),
+				pvLister:           f.pvInformer.Lister(),
+				pvObj:              test.nfsPV,
+				nfsServerNamespace: "openebs",
+				annotationPrefix:   "nfs.",
+			}
+
+			deployObj, err := nfsVolume.getNFSServerDeployment("openebs")
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if !reflect.DeepEqual(deployObj, test.nfsServerDeployment) {
+				t.Fatalf("%q test failed expected no diff but got \n%s", name, cmp.Diff(test.nfsServerDeployment, deployObj))
+			}
+			serviceObj, err := nfsVolume.getNFSServerService("openebs")
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if !reflect.DeepEqual(serviceObj, test.nfsServerService) {
+				t.Fatalf("%q test failed expected no diff but got \n%s", name, cmp.Diff(test.nfsServerService, serviceObj))
+			}
+
+			err = nfsVolume.RemoveEventFinalizer()
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if test.nfsServerDeployment != nil {
+				deployObj, err := f.clientset.AppsV1().Deployments("openebs").Get(context.TODO(), test.nfsServerDeployment.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("error shouldn't occur while fetching deployment %s error: %v", test.nfsServerDeployment.Name, err)
+				}
+				if helper.RemoveFinalizer(&deployObj.ObjectMeta, eventFinalizer) {
+					t.Fatalf("event finalizer shouldn't exist on deployment %s", deployObj.Name)
+				}
+			}
+			if test.nfsServerService != nil {
+				serviceObj, err := f.clientset.CoreV1().Services("openebs").Get(context.TODO(), test.nfsServerService.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("error shouldn't occur while fetching service %s error: %v", test.nfsServerService.Name, err)
+				}
+				if helper.RemoveFinalizer(&serviceObj.ObjectMeta, eventFinalizer) {
+					t.Fatalf("event finalizer shouldn't exist on service %s", serviceObj.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestAddEventFinalizer(t *testing.T) {
+	f := newFixture()
+	eventFinalizer := "nfs." + collectorinterface.VolumeEventsFinalizer
+	tests := map[string]struct {
+		nfsPV               *corev1.PersistentVolume
+		backendPVC          *corev1.PersistentVolumeClaim
+		backendPV           *corev1.PersistentVolume
+		nfsServerDeployment *appsv1.Deployment
+		nfsServerService    *corev1.Service
+		isErrExpected       bool
+	}{
+		"when finalizers doesn't exist on volume resources": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "pv1",
+					Finalizers: []string{"kubernetes.io/pv-protection"},
+				},
+			},
+			backendPVC: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "nfs-pv1",
+					Namespace: "openebs",
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					VolumeName: "backend-pv1",
+				},
+			},
+			backendPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "backend-pv1",
+				},
+			},
+			nfsServerDeployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "nfs-pv1",
+					Namespace: "openebs",
+				},
+			},
+			nfsServerService: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "nfs-pv1",
+					Namespace: "openebs",
+				},
+			},
+		},
+		"when finalizers already exist on volume resources": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "pv2",
+					Finalizers: []string{eventFinalizer},
+				},
+			},
+			backendPVC: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "nfs-pv2",
+					Namespace:  "openebs",
+					Finalizers: []string{eventFinalizer},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					VolumeName: "backend-pv2",
+				},
+			},
+			backendPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "backend-pv2",
+					Finalizers: []string{"kubernetes.io/pv-protection", eventFinalizer},
+				},
+			},
+		},
+		"when backing PVC is not yet bound": {
+			nfsPV: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pv3",
+				},
+			},
+			backendPVC: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "nfs-pv3",
+					Namespace: "openebs",
+				},
+			},
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			err := f.preCreateResources(nil, test.backendPVC, test.nfsPV, test.backendPV)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur during pre-resource creation but got error %v", name, err)
+			}
+			if test.nfsServerDeployment != nil {
+				_, err := f.clientset.AppsV1().Deployments("openebs").Create(context.TODO(), test.nfsServerDeployment, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatalf("%q test failed expected error not to occur while creating deployment but got %v", name, err)
+				}
+			}
+			if test.nfsServerService != nil {
+				_, err := f.clientset.CoreV1().Services("openebs").Create(context.TODO(), test.nfsServerService, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatalf("%q test failed expected error not to occur while creating service but got %v", name, err)
+				}
+			}
+			nfsVolume := &nfsVolume{
+				clientset:          f.clientset,
+				pvcLister:          f.pvcInformer.Lister(),
+				pvLister:           f.pvInformer.Lister(),
+				pvObj:              test.nfsPV,
+				nfsServerNamespace: "openebs",
+				annotationPrefix:   "nfs.",
+			}
+			updatedPV, err := nfsVolume.AddEventFinalizer(test.nfsPV.DeepCopy())
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if test.isErrExpected {
+				return
+			}
+			// Existing finalizers must be retained
+			for _, finalizer := range test.nfsPV.Finalizers {
+				if !helper.RemoveFinalizer(updatedPV.DeepCopy().GetObjectMeta().(*metav1.ObjectMeta), finalizer) {
+					t.Errorf("%q test failed expected finalizer %s to be retained on PV %s", name, finalizer, updatedPV.Name)
+				}
+			}
+			pv, err := f.clientset.CoreV1().PersistentVolumes().Get(context.TODO(), test.nfsPV.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("error shouldn't occur while fetching PV %s error: %v", test.nfsPV.Name, err)
+			}
+			if !helper.RemoveFinalizer(&pv.ObjectMeta, eventFinalizer) || len(pv.Finalizers) != len(test.nfsPV.Finalizers)-countFinalizer(test.nfsPV.Finalizers, eventFinalizer) {
+				t.Errorf("%q test failed expected event finalizer to exist once on PV %s but got %v", name, test.nfsPV.Name, pv.Finalizers)
+			}
+			pvc, err := f.clientset.CoreV1().PersistentVolumeClaims(test.backendPVC.Namespace).Get(context.TODO(), test.backendPVC.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("error shouldn't occur while fetching PVC %s error: %v", test.backendPVC.Name, err)
+			}
+			if !helper.RemoveFinalizer(&pvc.ObjectMeta, eventFinalizer) {
+				t.Errorf("%q test failed expected event finalizer to exist on PVC %s/%s", name, pvc.Namespace, pvc.Name)
+			}
+			backendPV, err := f.clientset.CoreV1().PersistentVolumes().Get(context.TODO(), test.backendPV.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("error shouldn't occur while fetching PV %s error: %v", test.backendPV.Name, err)
+			}
+			if !helper.RemoveFinalizer(&backendPV.ObjectMeta, eventFinalizer) {
+				t.Errorf("%q test failed expected event finalizer to exist on PV %s", name, backendPV.Name)
+			}
+			if test.nfsServerDeployment != nil {
+				deployObj, err := f.clientset.AppsV1().Deployments("openebs").Get(context.TODO(), test.nfsServerDeployment.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("error shouldn't occur while fetching deployment %s error: %v", test.nfsServerDeployment.Name, err)
+				}
+				if !helper.RemoveFinalizer(&deployObj.ObjectMeta, eventFinalizer) {
+					t.Errorf("%q test failed expected event finalizer to exist on deployment %s/%s", name, deployObj.Namespace, deployObj.Name)
+				}
+			}
+			if test.nfsServerService != nil {
+				serviceObj, err := f.clientset.CoreV1().Services("openebs").Get(context.TODO(), test.nfsServerService.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("error shouldn't occur while fetching service %s error: %v", test.nfsServerService.Name, err)
+				}
+				if !helper.RemoveFinalizer(&serviceObj.ObjectMeta, eventFinalizer) {
+					t.Errorf("%q test failed expected event finalizer to exist on service %s/%s", name, serviceObj.Namespace, serviceObj.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveEventFinalizerWhenNFSServerIsNotAccessible(t *testing.T) {
+	f := newFixture()
+	eventFinalizer := "nfs." + collectorinterface.VolumeEventsFinalizer
+	nfsPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "pv1",
+			Finalizers: []string{eventFinalizer},
+		},
+	}
+	backendPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "nfs-pv1",
+			Namespace:  "openebs",
+			Finalizers: []string{eventFinalizer},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeName: "backend-pv1",
+		},
+	}
+	backendPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "backend-pv1",
+			Finalizers: []string{eventFinalizer},
+		},
+	}
+	if err := f.preCreateResources(nil, backendPVC, nfsPV, backendPV); err != nil {
+		t.Fatalf("expected error not to occur during pre-resource creation but got error %v", err)
+	}
+	f.clientset.(*fake.Clientset).PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(appsv1.Resource("deployments"), "nfs-pv1", errors.New("forbidden"))
+	})
+	f.clientset.(*fake.Clientset).PrependReactor("get", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewInternalError(errors.New("etcd is unavailable"))
+	})
+	nfsVolume := &nfsVolume{
+		clientset:          f.clientset,
+		pvcLister:          f.pvcInformer.Lister(),
+		pvLister:           f.pvInformer.Lister(),
+		pvObj:              nfsPV.DeepCopy(),
+		nfsServerNamespace: "openebs",
+		annotationPrefix:   "nfs.",
+	}
+
+	if err := nfsVolume.RemoveEventFinalizer(); err != nil {
+		t.Fatalf("expected error not to occur but got %v", err)
+	}
+	for _, pvName := range []string{nfsPV.Name, backendPV.Name} {
+		pv, err := f.clientset.CoreV1().PersistentVolumes().Get(context.TODO(), pvName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("error shouldn't occur while fetching PV %s error: %v", pvName, err)
+		}
+		if helper.RemoveFinalizer(&pv.ObjectMeta, eventFinalizer) {
+			t.Errorf("expected event finalizer to be removed from PV %s", pvName)
+		}
+	}
+	pvc, err := f.clientset.CoreV1().PersistentVolumeClaims(backendPVC.Namespace).Get(context.TODO(), backendPVC.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error shouldn't occur while fetching PVC %s error: %v", backendPVC.Name, err)
+	}
+	if helper.RemoveFinalizer(&pvc.ObjectMeta, eventFinalizer) {
+		t.Errorf("expected event finalizer to be removed from PVC %s/%s", pvc.Namespace, pvc.Name)
+	}
+}
+
+func countFinalizer(finalizers []string, finalizer string) int {
+	var count int
+	for _, existingFinalizer := range finalizers {
+		if existingFinalizer == finalizer {
+			count++
+		}
+	}
+	return count
+}
+
+func TestStorageClassSnapshotOfDeletedStorageClass(t *testing.T) {
+	f := newFixture()
+	deletionTimestamp := metav1.Now()
+	nfsPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pv1",
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: "openebs-rwx",
+			ClaimRef: &corev1.ObjectReference{
+				Name:      "pvc1",
+				Namespace: "ns1",
+			},
+		},
+	}
+	backendPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nfs-pv1",
+			Namespace: "openebs",
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeName: "backend-pv1",
+		},
+	}
+	backendPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "backend-pv1",
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: "openebs-hostpath",
+		},
+	}
+	if err := f.preCreateResources(nil, backendPVC, nfsPV, backendPV); err != nil {
+		t.Fatalf("expected error not to occur during pre-resource creation but got error %v", err)
+	}
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	storageClassInformer := kubeInformerFactory.Storage().V1().StorageClasses()
+	storageClasses := []*storagev1.StorageClass{
+		{ObjectMeta: metav1.ObjectMeta{Name: "openebs-rwx", UID: "sc-uid-1"}, Provisioner: "openebs.io/nfsrwx"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "openebs-hostpath", UID: "sc-uid-2"}, Provisioner: "openebs.io/local"},
+	}
+	for _, storageClass := range storageClasses {
+		if err := storageClassInformer.Informer().GetIndexer().Add(storageClass); err != nil {
+			t.Fatalf("failed to add StorageClass into cache: %v", err)
+		}
+	}
+	newNFSVolume := func(pvObj *corev1.PersistentVolume) *nfsVolume {
+		return &nfsVolume{
+			clientset:          f.clientset,
+			pvcLister:          f.pvcInformer.Lister(),
+			pvLister:           f.pvInformer.Lister(),
+			pvObj:              pvObj,
+			nfsServerNamespace: "openebs",
+			annotationPrefix:   "nfs.",
+			dataType:           collectorinterface.JSONDataType,
+			resolvers: enrichment.Resolvers{
+				StorageClass: enrichment.NewStorageClassResolver(storageClassInformer),
+			},
+		}
+	}
+
+	createVolume := newNFSVolume(nfsPV)
+	if _, err := createVolume.CollectCreateEvents(); err != nil {
+		t.Fatalf("expected error not to occur while collecting create event but got %v", err)
+	}
+	annotatedPV, err := createVolume.AnnotateCreateEvent(nfsPV.DeepCopy())
+	if err != nil {
+		t.Fatalf("expected error not to occur while annotating create event but got %v", err)
+	}
+	if _, isExist := annotatedPV.Annotations["nfs."+collectorinterface.StorageClassSnapshotAnnotation]; !isExist {
+		t.Fatalf("expected StorageClass snapshots to be persisted on PV but got annotations %v", annotatedPV.Annotations)
+	}
+
+	// StorageClasses are deleted before volume
+	for _, storageClass := range storageClasses {
+		if err := storageClassInformer.Informer().GetIndexer().Delete(storageClass); err != nil {
+			t.Fatalf("failed to delete StorageClass from cache: %v", err)
+		}
+	}
+	annotatedPV.DeletionTimestamp = &deletionTimestamp
+	rawData, err := newNFSVolume(annotatedPV).CollectDeleteEvents()
+	if err != nil {
+		t.Fatalf("expected error not to occur while collecting delete event but got %v", err)
+	}
+	deleteData := &NFSDeleteVolumeData{}
+	if err := json.Unmarshal([]byte(rawData), deleteData); err != nil {
+		t.Fatalf("expected error not to occur during unmarshal of data error: %v", err)
+	}
+	if deleteData.VolumeDeleted.NFSStorageClass == nil || deleteData.VolumeDeleted.NFSStorageClass.UID != "sc-uid-1" {
+		t.Fatalf("expected NFS StorageClass snapshot of create event but got %+v", deleteData.VolumeDeleted.NFSStorageClass)
+	}
+	if deleteData.VolumeDeleted.BackingStorageClass == nil || deleteData.VolumeDeleted.BackingStorageClass.UID != "sc-uid-2" {
+		t.Fatalf("expected backing StorageClass snapshot of create event but got %+v", deleteData.VolumeDeleted.BackingStorageClass)
+	}
+	if _, isExist := deleteData.VolumeDeleted.NFSPV.Annotations["nfs."+collectorinterface.StorageClassSnapshotAnnotation]; isExist {
+		t.Fatalf("expected StorageClass snapshot annotation not to be part of NFS PV in payload")
+	}
+}
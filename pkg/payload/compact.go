@@ -0,0 +1,161 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payload
+
+import (
+	// embed is required to publish the JSON Schema of compact schema
+	_ "embed"
+	"encoding/json"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// RawSchema sends the data as collected by the collector
+	RawSchema = "raw"
+	// CompactSchema sends the data as CompactVolumeEvent
+	CompactSchema = "compact"
+
+	// CompactSchemaVersion is the version of CompactVolumeEvent
+	CompactSchemaVersion = "v1"
+)
+
+// CompactSchemaJSON is the JSON Schema document of CompactVolumeEvent
+//
+//go:embed compact_volume_event.schema.json
+var CompactSchemaJSON string
+
+// CompactVolumeEvent is the compact typed form of volume event which
+// doesn't require receivers to understand Kubernetes objects. Any
+// change here must be reflected in compact_volume_event.schema.json
+type CompactVolumeEvent struct {
+	SchemaVersion string                       `json:"schema_version"`
+	EventType     collectorinterface.EventType `json:"event_type"`
+	// VolumeID is the name of the PV
+	VolumeID  string `json:"volume_id"`
+	VolumeUID string `json:"volume_uid"`
+	CASType   string `json:"cas_type"`
+
+	PVCNamespace string `json:"pvc_namespace,omitempty"`
+	PVCName      string `json:"pvc_name,omitempty"`
+	StorageClass string `json:"storage_class,omitempty"`
+
+	// RequestedCapacityBytes is omitted if PVC doesn't exist
+	RequestedCapacityBytes   *int64   `json:"requested_capacity_bytes,omitempty"`
+	ProvisionedCapacityBytes *int64   `json:"provisioned_capacity_bytes,omitempty"`
+	AccessModes              []string `json:"access_modes"`
+	ReclaimPolicy            string   `json:"reclaim_policy,omitempty"`
+
+	// BackingVolumeID is the name of PV backing the volume
+	BackingVolumeID string `json:"backing_volume_id,omitempty"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// Tenant holds the tenant fields resolved by the collector
+	Tenant map[string]string `json:"tenant,omitempty"`
+	// Origin is synthetic/backfilled for create events of volumes
+	// provisioned before volume events are enabled
+	Origin string `json:"origin,omitempty"`
+}
+
+// CompactSchemaTransformer converts the collected data into CompactVolumeEvent
+type CompactSchemaTransformer struct{}
+
+// NewCompactSchemaTransformer returns the transformer of compact schema
+func NewCompactSchemaTransformer() *CompactSchemaTransformer {
+	return &CompactSchemaTransformer{}
+}
+
+// Transform returns the serialized CompactVolumeEvent of collected data
+func (c *CompactSchemaTransformer) Transform(metadata collectorinterface.EventMetadata, data string) (string, error) {
+	event, err := newCompactVolumeEvent(metadata, data)
+	if err != nil {
+		return "", err
+	}
+	compactData, err := json.Marshal(event)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal compact volume event")
+	}
+	return string(compactData), nil
+}
+
+// newCompactVolumeEvent converts the serialized data collected
+// by collector of given CAS type into CompactVolumeEvent
+func newCompactVolumeEvent(metadata collectorinterface.EventMetadata, data string) (*CompactVolumeEvent, error) {
+	event := &CompactVolumeEvent{
+		SchemaVersion: CompactSchemaVersion,
+		EventType:     metadata.EventType,
+		VolumeID:      metadata.PVName,
+		VolumeUID:     metadata.PVUID,
+		CASType:       metadata.CASType,
+		Origin:        metadata.Origin,
+	}
+
+	switch metadata.CASType {
+	case nfspv.OpenEBSNFSCASLabelValue:
+		volumeData, tenant, err := nfspv.UnmarshalNFSVolumeData(metadata.EventType, data)
+		if err != nil {
+			return nil, err
+		}
+		event.fillNFSVolumeData(volumeData)
+		event.Tenant = tenant
+	default:
+		return nil, errors.Errorf("conversion of %s volume data into compact schema is not supported", metadata.CASType)
+	}
+	return event, nil
+}
+
+func (c *CompactVolumeEvent) fillNFSVolumeData(volumeData *nfspv.NFSVolumeData) {
+	pv := volumeData.NFSPV
+	c.StorageClass = pv.Spec.StorageClassName
+	c.ReclaimPolicy = string(pv.Spec.PersistentVolumeReclaimPolicy)
+	c.AccessModes = []string{}
+	for _, accessMode := range pv.Spec.AccessModes {
+		c.AccessModes = append(c.AccessModes, string(accessMode))
+	}
+	if capacity, isExist := pv.Spec.Capacity[corev1.ResourceStorage]; isExist {
+		c.ProvisionedCapacityBytes = int64Ptr(capacity.Value())
+	}
+	c.CreatedAt = pv.CreationTimestamp.UTC()
+	if pv.DeletionTimestamp != nil {
+		deletedAt := pv.DeletionTimestamp.UTC()
+		c.DeletedAt = &deletedAt
+	}
+
+	if pvc := volumeData.NFSPVC; pvc != nil {
+		c.PVCNamespace = pvc.Namespace
+		c.PVCName = pvc.Name
+		if requested, isExist := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; isExist {
+			c.RequestedCapacityBytes = int64Ptr(requested.Value())
+		}
+	} else if pv.Spec.ClaimRef != nil {
+		c.PVCNamespace = pv.Spec.ClaimRef.Namespace
+		c.PVCName = pv.Spec.ClaimRef.Name
+	}
+	if volumeData.BackingPV != nil {
+		c.BackingVolumeID = volumeData.BackingPV.Name
+	}
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
@@ -0,0 +1,217 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payload
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/mayadata-io/volume-events-exporter/pkg/nfspv"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestNFSVolumeData(isDeleted, isPVCExist bool) *nfspv.NFSVolumeData {
+	volumeData := &nfspv.NFSVolumeData{
+		NFSPV: &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pvc-1",
+				UID:               "uid-1",
+				CreationTimestamp: metav1.NewTime(time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC)),
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName:              "openebs-rwx",
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("5Gi"),
+				},
+				ClaimRef: &corev1.ObjectReference{Namespace: "app", Name: "data"},
+			},
+		},
+		BackingPV: &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-2"},
+		},
+	}
+	if isPVCExist {
+		volumeData.NFSPVC = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "data"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("4Gi"),
+					},
+				},
+			},
+		}
+	}
+	if isDeleted {
+		deletedAt := metav1.NewTime(time.Date(2021, 10, 3, 10, 0, 0, 0, time.UTC))
+		volumeData.NFSPV.DeletionTimestamp = &deletedAt
+	}
+	return volumeData
+}
+
+func TestCompactSchemaTransform(t *testing.T) {
+	deletedAt := time.Date(2021, 10, 3, 10, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		metadata      collectorinterface.EventMetadata
+		data          interface{}
+		expectedEvent *CompactVolumeEvent
+		isErrExpected bool
+	}{
+		"when create event of NFS volume is converted": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				PVName:    "pvc-1",
+				PVUID:     "uid-1",
+				CASType:   nfspv.OpenEBSNFSCASLabelValue,
+			},
+			data: nfspv.NFSCreateVolumeData{
+				VolumeProvisioned: newTestNFSVolumeData(false, true),
+				Tenant:            map[string]string{"team": "storage"},
+			},
+			expectedEvent: &CompactVolumeEvent{
+				SchemaVersion:            CompactSchemaVersion,
+				EventType:                collectorinterface.CreateEventType,
+				VolumeID:                 "pvc-1",
+				VolumeUID:                "uid-1",
+				CASType:                  nfspv.OpenEBSNFSCASLabelValue,
+				PVCNamespace:             "app",
+				PVCName:                  "data",
+				StorageClass:             "openebs-rwx",
+				RequestedCapacityBytes:   int64Ptr(4 * 1024 * 1024 * 1024),
+				ProvisionedCapacityBytes: int64Ptr(5 * 1024 * 1024 * 1024),
+				AccessModes:              []string{"ReadWriteMany"},
+				ReclaimPolicy:            "Delete",
+				BackingVolumeID:          "pvc-2",
+				CreatedAt:                time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC),
+				Tenant:                   map[string]string{"team": "storage"},
+			},
+		},
+		"when delete event of NFS volume without NFS PVC is converted": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.DeleteEventType,
+				PVName:    "pvc-1",
+				PVUID:     "uid-1",
+				CASType:   nfspv.OpenEBSNFSCASLabelValue,
+			},
+			data: nfspv.NFSDeleteVolumeData{VolumeDeleted: newTestNFSVolumeData(true, false)},
+			expectedEvent: &CompactVolumeEvent{
+				SchemaVersion:            CompactSchemaVersion,
+				EventType:                collectorinterface.DeleteEventType,
+				VolumeID:                 "pvc-1",
+				VolumeUID:                "uid-1",
+				CASType:                  nfspv.OpenEBSNFSCASLabelValue,
+				PVCNamespace:             "app",
+				PVCName:                  "data",
+				StorageClass:             "openebs-rwx",
+				ProvisionedCapacityBytes: int64Ptr(5 * 1024 * 1024 * 1024),
+				AccessModes:              []string{"ReadWriteMany"},
+				ReclaimPolicy:            "Delete",
+				BackingVolumeID:          "pvc-2",
+				CreatedAt:                time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC),
+				DeletedAt:                &deletedAt,
+			},
+		},
+		"when volume data is missing": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.DeleteEventType,
+				CASType:   nfspv.OpenEBSNFSCASLabelValue,
+			},
+			data:          nfspv.NFSDeleteVolumeData{},
+			isErrExpected: true,
+		},
+		"when CAS type is not supported": {
+			metadata: collectorinterface.EventMetadata{
+				EventType: collectorinterface.CreateEventType,
+				CASType:   "unknown",
+			},
+			data:          nfspv.NFSCreateVolumeData{},
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(test.data)
+			if err != nil {
+				t.Fatalf("%q test failed to marshal data: %v", name, err)
+			}
+			output, err := NewCompactSchemaTransformer().Transform(test.metadata, string(data))
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if test.isErrExpected {
+				return
+			}
+			event := &CompactVolumeEvent{}
+			if err := json.Unmarshal([]byte(output), event); err != nil {
+				t.Fatalf("%q test failed to unmarshal compact event: %v", name, err)
+			}
+			if diff := cmp.Diff(test.expectedEvent, event); diff != "" {
+				t.Fatalf("%q test failed compact event mismatch (-want +got):\n%s", name, diff)
+			}
+		})
+	}
+}
+
+// TestCompactSchemaJSON verifies that published JSON Schema is in sync with CompactVolumeEvent
+func TestCompactSchemaJSON(t *testing.T) {
+	schema := struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}{}
+	if err := json.Unmarshal([]byte(CompactSchemaJSON), &schema); err != nil {
+		t.Fatalf("failed to unmarshal JSON Schema: %v", err)
+	}
+
+	var fields, required []string
+	eventType := reflect.TypeOf(CompactVolumeEvent{})
+	for i := 0; i < eventType.NumField(); i++ {
+		tag := strings.Split(eventType.Field(i).Tag.Get("json"), ",")
+		fields = append(fields, tag[0])
+		if len(tag) == 1 {
+			required = append(required, tag[0])
+		}
+	}
+	var properties []string
+	for property := range schema.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(fields)
+	sort.Strings(properties)
+	sort.Strings(required)
+	sort.Strings(schema.Required)
+	if diff := cmp.Diff(fields, properties); diff != "" {
+		t.Fatalf("JSON Schema properties mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(required, schema.Required); diff != "" {
+		t.Fatalf("JSON Schema required properties mismatch (-want +got):\n%s", diff)
+	}
+}
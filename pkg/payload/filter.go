@@ -0,0 +1,262 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payload
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+)
+
+const (
+	// redactedValue replaces the string values of redacted fields
+	redactedValue = "[REDACTED]"
+)
+
+var (
+	// quantityMapFields hold resource quantities encoded as strings
+	quantityMapFields = map[string]bool{
+		"capacity":    true,
+		"allocatable": true,
+		"requests":    true,
+		"limits":      true,
+	}
+	// intOrStringFields hold either integer or string values
+	intOrStringFields = map[string]bool{
+		"targetPort":     true,
+		"maxSurge":       true,
+		"maxUnavailable": true,
+	}
+)
+
+// FilterConfig defines what has to be dropped or redacted from
+// Kubernetes objects(JSON objects holding metadata) in payload
+type FilterConfig struct {
+	// KeepManagedFields retains metadata.managedFields
+	KeepManagedFields bool
+
+	// LabelAllowList and AnnotationAllowList are glob patterns of keys
+	// to retain, all keys are retained if list is empty. '*' matches any
+	// sequence of characters including '/' of prefixed keys
+	LabelAllowList      []string
+	AnnotationAllowList []string
+
+	// LabelDenyList and AnnotationDenyList are glob patterns of
+	// keys to drop, evaluated after allow list
+	LabelDenyList      []string
+	AnnotationDenyList []string
+
+	// RedactFields are dotted paths of fields relative to the object
+	// ex: spec.csi.volumeAttributes. String values are replaced with
+	// [REDACTED] and values of other types are dropped. Fields whose
+	// string value is decoded into typed value(timestamps, quantities)
+	// are rejected since [REDACTED] can't be decoded by typed schemas
+	RedactFields []string
+}
+
+// Filter drops and redacts fields of Kubernetes objects in payload
+type Filter struct {
+	config FilterConfig
+
+	// key patterns compiled from allow and deny lists
+	labelAllowList      []*regexp.Regexp
+	labelDenyList       []*regexp.Regexp
+	annotationAllowList []*regexp.Regexp
+	annotationDenyList  []*regexp.Regexp
+
+	// redactPaths are RedactFields split into path segments
+	redactPaths [][]string
+}
+
+// NewFilter validates the patterns and returns the filter
+func NewFilter(config FilterConfig) (*Filter, error) {
+	filter := &Filter{config: config}
+	for _, keyPatterns := range []struct {
+		patterns []string
+		compiled *[]*regexp.Regexp
+	}{
+		{config.LabelAllowList, &filter.labelAllowList},
+		{config.LabelDenyList, &filter.labelDenyList},
+		{config.AnnotationAllowList, &filter.annotationAllowList},
+		{config.AnnotationDenyList, &filter.annotationDenyList},
+	} {
+		for _, pattern := range keyPatterns.patterns {
+			compiled, err := compileKeyPattern(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid key pattern %q", pattern)
+			}
+			*keyPatterns.compiled = append(*keyPatterns.compiled, compiled)
+		}
+	}
+
+	for _, field := range config.RedactFields {
+		segments := strings.Split(field, ".")
+		for _, segment := range segments {
+			if segment == "" {
+				return nil, errors.Errorf("invalid field path %q to redact", field)
+			}
+		}
+		if isTypedStringField(segments) {
+			return nil, errors.Errorf("field %q holds a typed value which can't be redacted", field)
+		}
+		filter.redactPaths = append(filter.redactPaths, segments)
+	}
+	return filter, nil
+}
+
+// Transform returns the data after filtering Kubernetes objects in it
+func (f *Filter) Transform(_ collectorinterface.EventMetadata, data string) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal collected data")
+	}
+	f.walk(value)
+	filteredData, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal filtered data")
+	}
+	return string(filteredData), nil
+}
+
+// walk filters every Kubernetes object found in given value
+func (f *Filter) walk(value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if metadata, ok := value["metadata"].(map[string]interface{}); ok {
+			f.filterObject(value, metadata)
+		}
+		for _, child := range value {
+			f.walk(child)
+		}
+	case []interface{}:
+		for _, child := range value {
+			f.walk(child)
+		}
+	}
+}
+
+func (f *Filter) filterObject(obj, metadata map[string]interface{}) {
+	if !f.config.KeepManagedFields {
+		delete(metadata, "managedFields")
+	}
+	filterKeys(metadata, "labels", f.labelAllowList, f.labelDenyList)
+	filterKeys(metadata, "annotations", f.annotationAllowList, f.annotationDenyList)
+	for _, redactPath := range f.redactPaths {
+		redact(obj, redactPath)
+	}
+}
+
+// filterKeys drops the keys of metadata[field] which doesn't
+// match allow list(if any) or matches deny list
+func filterKeys(metadata map[string]interface{}, field string, allowList, denyList []*regexp.Regexp) {
+	values, ok := metadata[field].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key := range values {
+		if (len(allowList) != 0 && !matchesAny(allowList, key)) || matchesAny(denyList, key) {
+			delete(values, key)
+		}
+	}
+}
+
+func matchesAny(patterns []*regexp.Regexp, key string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileKeyPattern converts the glob pattern of label or annotation key
+// into regexp. Unlike path.Match '*' and '?' also match '/' so that
+// patterns like app.* match prefixed keys ex: app.kubernetes.io/name.
+// Character classes([abc], [!abc]) and '\' escapes are supported
+func compileKeyPattern(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '\\':
+			i++
+			if i == len(pattern) {
+				return nil, errors.Errorf("trailing escape character")
+			}
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end <= 0 {
+				return nil, errors.Errorf("unterminated or empty character class")
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// isTypedStringField returns true if field at given path holds a
+// string which is decoded into typed value ex: metadata.creationTimestamp,
+// spec.capacity.storage. Replacing such values breaks typed unmarshal
+// of payload by compact schema, gRPC and chatops
+func isTypedStringField(segments []string) bool {
+	leaf := segments[len(segments)-1]
+	if strings.HasSuffix(leaf, "Timestamp") || strings.HasSuffix(leaf, "Time") {
+		return true
+	}
+	if intOrStringFields[leaf] {
+		return true
+	}
+	return len(segments) > 1 && quantityMapFields[segments[len(segments)-2]]
+}
+
+// redact replaces the field at given path if it is
+// string value or else drops it from the object
+func redact(obj map[string]interface{}, segments []string) {
+	for _, segment := range segments[:len(segments)-1] {
+		child, ok := obj[segment].(map[string]interface{})
+		if !ok {
+			return
+		}
+		obj = child
+	}
+	key := segments[len(segments)-1]
+	value, isExist := obj[key]
+	if !isExist {
+		return
+	}
+	if _, isString := value.(string); isString {
+		obj[key] = redactedValue
+		return
+	}
+	delete(obj, key)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payload
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const filterTestData = `{
+  "volume_provisioned": {
+    "nfs_pvc": null,
+    "nfs_pv": {
+      "metadata": {
+        "name": "pvc-1",
+        "managedFields": [{"manager": "nfs-provisioner"}],
+        "labels": {"team": "storage", "internal.openebs.io/id": "1"},
+        "annotations": {"pv.kubernetes.io/provisioned-by": "openebs.io/nfsrwx", "kubectl.kubernetes.io/last-applied-configuration": "{}"}
+      },
+      "spec": {"csi": {"driver": "openebs", "volumeAttributes": {"secret": "value"}}, "nfs": {"server": "10.0.0.1"}}
+    }
+  }
+}`
+
+func TestFilterTransform(t *testing.T) {
+	tests := map[string]struct {
+		config              FilterConfig
+		expectedPV          string
+		isConfigErrExpected bool
+	}{
+		"when default filter is applied": {
+			expectedPV: `{
+			  "metadata": {
+			    "name": "pvc-1",
+			    "labels": {"team": "storage", "internal.openebs.io/id": "1"},
+			    "annotations": {"pv.kubernetes.io/provisioned-by": "openebs.io/nfsrwx", "kubectl.kubernetes.io/last-applied-configuration": "{}"}
+			  },
+			  "spec": {"csi": {"driver": "openebs", "volumeAttributes": {"secret": "value"}}, "nfs": {"server": "10.0.0.1"}}
+			}`,
+		},
+		"when managed fields are retained and keys are filtered": {
+			config: FilterConfig{
+				KeepManagedFields:  true,
+				LabelAllowList:     []string{"team"},
+				AnnotationDenyList: []string{"kubectl.kubernetes.io/*"},
+			},
+			expectedPV: `{
+			  "metadata": {
+			    "name": "pvc-1",
+			    "managedFields": [{"manager": "nfs-provisioner"}],
+			    "labels": {"team": "storage"},
+			    "annotations": {"pv.kubernetes.io/provisioned-by": "openebs.io/nfsrwx"}
+			  },
+			  "spec": {"csi": {"driver": "openebs", "volumeAttributes": {"secret": "value"}}, "nfs": {"server": "10.0.0.1"}}
+			}`,
+		},
+		"when fields are redacted": {
+			config: FilterConfig{
+				LabelDenyList:       []string{"*.openebs.io/*"},
+				AnnotationAllowList: []string{"pv.kubernetes.io/*"},
+				RedactFields:        []string{"spec.csi.volumeAttributes", "spec.nfs.server", "spec.missing.field"},
+			},
+			expectedPV: `{
+			  "metadata": {
+			    "name": "pvc-1",
+			    "labels": {"team": "storage"},
+			    "annotations": {"pv.kubernetes.io/provisioned-by": "openebs.io/nfsrwx"}
+			  },
+			  "spec": {"csi": {"driver": "openebs"}, "nfs": {"server": "[REDACTED]"}}
+			}`,
+		},
+		"when wildcards match prefixed keys": {
+			config: FilterConfig{
+				LabelDenyList:       []string{"*openebs*"},
+				AnnotationAllowList: []string{"*"},
+				AnnotationDenyList:  []string{"kubectl.*"},
+			},
+			expectedPV: `{
+			  "metadata": {
+			    "name": "pvc-1",
+			    "labels": {"team": "storage"},
+			    "annotations": {"pv.kubernetes.io/provisioned-by": "openebs.io/nfsrwx"}
+			  },
+			  "spec": {"csi": {"driver": "openebs", "volumeAttributes": {"secret": "value"}}, "nfs": {"server": "10.0.0.1"}}
+			}`,
+		},
+		"when allow list matches prefixed keys": {
+			config: FilterConfig{
+				LabelAllowList:      []string{"internal.*"},
+				AnnotationAllowList: []string{"pv.kubernetes.io/provisioned-b?"},
+			},
+			expectedPV: `{
+			  "metadata": {
+			    "name": "pvc-1",
+			    "labels": {"internal.openebs.io/id": "1"},
+			    "annotations": {"pv.kubernetes.io/provisioned-by": "openebs.io/nfsrwx"}
+			  },
+			  "spec": {"csi": {"driver": "openebs", "volumeAttributes": {"secret": "value"}}, "nfs": {"server": "10.0.0.1"}}
+			}`,
+		},
+		"when key pattern is invalid": {
+			config:              FilterConfig{LabelDenyList: []string{"team["}},
+			isConfigErrExpected: true,
+		},
+		"when field path is invalid": {
+			config:              FilterConfig{RedactFields: []string{"spec..csi"}},
+			isConfigErrExpected: true,
+		},
+		"when timestamp field is redacted": {
+			config:              FilterConfig{RedactFields: []string{"metadata.creationTimestamp"}},
+			isConfigErrExpected: true,
+		},
+		"when quantity field is redacted": {
+			config:              FilterConfig{RedactFields: []string{"spec.capacity.storage"}},
+			isConfigErrExpected: true,
+		},
+		"when int or string field is redacted": {
+			config:              FilterConfig{RedactFields: []string{"spec.ports.targetPort"}},
+			isConfigErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			filter, err := NewFilter(test.config)
+			if test.isConfigErrExpected {
+				if err == nil {
+					t.Fatalf("%q test failed expected config error to occur but got nil", name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%q test failed expected config error not to occur but got %v", name, err)
+			}
+			output, err := filter.Transform(testMetadata, filterTestData)
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+
+			var got, expectedPV interface{}
+			if err := json.Unmarshal([]byte(output), &got); err != nil {
+				t.Fatalf("%q test failed to unmarshal output: %v", name, err)
+			}
+			if err := json.Unmarshal([]byte(test.expectedPV), &expectedPV); err != nil {
+				t.Fatalf("%q test failed to unmarshal expected PV: %v", name, err)
+			}
+			expected := map[string]interface{}{
+				"volume_provisioned": map[string]interface{}{
+					"nfs_pvc": nil,
+					"nfs_pv":  expectedPV,
+				},
+			}
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Fatalf("%q test failed output mismatch (-want +got):\n%s", name, diff)
+			}
+		})
+	}
+}
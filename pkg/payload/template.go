@@ -0,0 +1,120 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Templates are rendered against the context of following form
+//	{
+//	  "event_type": "create",
+//	  "pv_name": "pvc-1",
+//	  "pv_uid": "4b1c...",
+//	  "cas_type": "nfs-kernel",
+//	  "data": <collected data ex: {"volume_provisioned": {...}}>
+//	}
+// ex: {{ .data.volume_provisioned.nfs_pv.metadata.name }} is the name of NFS PV
+
+// GoTemplate renders the payload using Go text/template
+type GoTemplate struct {
+	template *template.Template
+}
+
+// JSONPathTemplate renders the payload using Kubernetes JSONPath
+type JSONPathTemplate struct {
+	jsonPath *jsonpath.JSONPath
+}
+
+// NewGoTemplate parses the given Go template. Function toJson
+// is available to the template to serialize values as JSON
+func NewGoTemplate(text string) (*GoTemplate, error) {
+	tmpl, err := template.New("payload").
+		Funcs(template.FuncMap{"toJson": toJSON}).
+		Option("missingkey=error").
+		Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse payload template")
+	}
+	return &GoTemplate{template: tmpl}, nil
+}
+
+// Transform renders the template against the collected data
+func (g *GoTemplate) Transform(metadata collectorinterface.EventMetadata, data string) (string, error) {
+	renderContext, err := newRenderContext(metadata, data)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := g.template.Execute(&out, renderContext); err != nil {
+		return "", errors.Wrapf(err, "failed to render payload template")
+	}
+	return out.String(), nil
+}
+
+// NewJSONPathTemplate parses the given JSONPath template
+// ex: {.data.volume_provisioned.nfs_pv.metadata.name}
+func NewJSONPathTemplate(text string) (*JSONPathTemplate, error) {
+	jsonPath := jsonpath.New("payload")
+	if err := jsonPath.Parse(text); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse payload JSONPath")
+	}
+	return &JSONPathTemplate{jsonPath: jsonPath}, nil
+}
+
+// Transform evaluates the JSONPath against the collected data
+func (j *JSONPathTemplate) Transform(metadata collectorinterface.EventMetadata, data string) (string, error) {
+	renderContext, err := newRenderContext(metadata, data)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := j.jsonPath.Execute(&out, renderContext); err != nil {
+		return "", errors.Wrapf(err, "failed to evaluate payload JSONPath")
+	}
+	return out.String(), nil
+}
+
+// newRenderContext returns the context against which templates are rendered
+func newRenderContext(metadata collectorinterface.EventMetadata, data string) (map[string]interface{}, error) {
+	var collectedData interface{}
+	if err := json.Unmarshal([]byte(data), &collectedData); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal collected data")
+	}
+	return map[string]interface{}{
+		"event_type": string(metadata.EventType),
+		"pv_name":    metadata.PVName,
+		"pv_uid":     metadata.PVUID,
+		"cas_type":   metadata.CASType,
+		"origin":     metadata.Origin,
+		"data":       collectedData,
+	}, nil
+}
+
+func toJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payload
+
+import (
+	"testing"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+)
+
+const testData = `{"volume_provisioned":{"nfs_pvc":null,"nfs_pv":{"metadata":{"name":"pvc-1","labels":{"team":"storage"}},"spec":{"capacity":{"storage":"5Gi"}}}}}`
+
+var testMetadata = collectorinterface.EventMetadata{
+	EventType: collectorinterface.CreateEventType,
+	PVName:    "pvc-1",
+	PVUID:     "uid-1",
+	CASType:   "nfs-kernel",
+}
+
+func TestGoTemplateTransform(t *testing.T) {
+	tests := map[string]struct {
+		template           string
+		data               string
+		expectedOutput     string
+		isParseErrExpected bool
+		isErrExpected      bool
+	}{
+		"when template renders billing payload": {
+			template:       `{"id":"{{ .pv_uid }}","action":"{{ .event_type }}","size":"{{ index .data.volume_provisioned.nfs_pv.spec.capacity "storage" }}"}`,
+			data:           testData,
+			expectedOutput: `{"id":"uid-1","action":"create","size":"5Gi"}`,
+		},
+		"when template serializes values using toJson": {
+			template:       `{{ toJson .data.volume_provisioned.nfs_pv.metadata.labels }}`,
+			data:           testData,
+			expectedOutput: `{"team":"storage"}`,
+		},
+		"when template renders origin of lifecycle event": {
+			template:       `{"origin":"{{ .origin }}"}`,
+			data:           testData,
+			expectedOutput: `{"origin":""}`,
+		},
+		"when template refers missing key": {
+			template:      `{{ .data.volume_deleted.nfs_pv }}`,
+			data:          testData,
+			isErrExpected: true,
+		},
+		"when collected data is not JSON": {
+			template:      `{{ .pv_name }}`,
+			data:          "pv: pvc-1",
+			isErrExpected: true,
+		},
+		"when template is malformed": {
+			template:           `{{ .pv_name `,
+			isParseErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			goTemplate, err := NewGoTemplate(test.template)
+			if test.isParseErrExpected {
+				if err == nil {
+					t.Fatalf("%q test failed expected parse error to occur but got nil", name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%q test failed expected parse error not to occur but got %v", name, err)
+			}
+			output, err := goTemplate.Transform(testMetadata, test.data)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if output != test.expectedOutput {
+				t.Fatalf("%q test failed expected output %q but got %q", name, test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestJSONPathTemplateTransform(t *testing.T) {
+	tests := map[string]struct {
+		template           string
+		expectedOutput     string
+		isParseErrExpected bool
+		isErrExpected      bool
+	}{
+		"when JSONPath projects fields": {
+			template:       `{.pv_uid},{.data.volume_provisioned.nfs_pv.spec.capacity.storage}`,
+			expectedOutput: "uid-1,5Gi",
+		},
+		"when JSONPath refers missing key": {
+			template:      `{.data.volume_deleted}`,
+			isErrExpected: true,
+		},
+		"when JSONPath is malformed": {
+			template:           `{.pv_uid`,
+			isParseErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			jsonPathTemplate, err := NewJSONPathTemplate(test.template)
+			if test.isParseErrExpected {
+				if err == nil {
+					t.Fatalf("%q test failed expected parse error to occur but got nil", name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%q test failed expected parse error not to occur but got %v", name, err)
+			}
+			output, err := jsonPathTemplate.Transform(testMetadata, testData)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if output != test.expectedOutput {
+				t.Fatalf("%q test failed expected output %q but got %q", name, test.expectedOutput, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payload
+
+import (
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+)
+
+// Transformer transforms the data collected by a
+// collector before it is sent to the destination
+type Transformer interface {
+	// Transform returns the transformed form of given data
+	Transform(metadata collectorinterface.EventMetadata, data string) (string, error)
+}
+
+// transformingSender transforms the data and sends it to the wrapped sender
+type transformingSender struct {
+	collectorinterface.EventsSender
+	transformers []Transformer
+}
+
+// NewTransformingSenderBuilder returns the builder of events sender
+// which applies given transformers in order on the collected data
+// before sending it with the sender built by given builder
+func NewTransformingSenderBuilder(builder collectorinterface.EventsSenderBuilder, transformers ...Transformer) collectorinterface.EventsSenderBuilder {
+	if len(transformers) == 0 {
+		return builder
+	}
+	return func(collector collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+		return &transformingSender{
+			EventsSender: builder(collector),
+			transformers: transformers,
+		}
+	}
+}
+
+// Send transforms the data and sends it to destination
+func (t *transformingSender) Send(metadata collectorinterface.EventMetadata, data string) error {
+	dataType := t.GetDataType()
+	if dataType != collectorinterface.JSONDataType {
+		return errors.Errorf("transforming %s data is not supported", dataType)
+	}
+	var err error
+	for _, transformer := range t.transformers {
+		data, err = transformer.Transform(metadata, data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to transform %s event of volume %s", metadata.EventType, metadata.PVName)
+		}
+	}
+	return t.EventsSender.Send(metadata, data)
+}
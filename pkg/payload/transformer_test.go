@@ -0,0 +1,88 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payload
+
+import (
+	"testing"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+)
+
+// fakeSender records the data sent to it
+type fakeSender struct {
+	collectorinterface.VolumeEventCollector
+	dataType collectorinterface.DataType
+	sentData string
+}
+
+func (f *fakeSender) GetDataType() collectorinterface.DataType {
+	return f.dataType
+}
+
+func (f *fakeSender) Send(_ collectorinterface.EventMetadata, data string) error {
+	f.sentData = data
+	return nil
+}
+
+func TestTransformingSenderSend(t *testing.T) {
+	tests := map[string]struct {
+		dataType      collectorinterface.DataType
+		template      string
+		expectedData  string
+		isErrExpected bool
+	}{
+		"when template is applied on JSON data": {
+			dataType:     collectorinterface.JSONDataType,
+			template:     `{"volume":"{{ .pv_name }}"}`,
+			expectedData: `{"volume":"pvc-1"}`,
+		},
+		"when collector data type is not JSON": {
+			dataType:      collectorinterface.YAMLDataType,
+			template:      `{{ .pv_name }}`,
+			isErrExpected: true,
+		},
+		"when template fails to render": {
+			dataType:      collectorinterface.JSONDataType,
+			template:      `{{ .unknown }}`,
+			isErrExpected: true,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			goTemplate, err := NewGoTemplate(test.template)
+			if err != nil {
+				t.Fatalf("%q test failed to parse template: %v", name, err)
+			}
+			sender := &fakeSender{dataType: test.dataType}
+			builder := NewTransformingSenderBuilder(func(_ collectorinterface.VolumeEventCollector) collectorinterface.EventsSender {
+				return sender
+			}, goTemplate)
+			err = builder(nil).Send(testMetadata, testData)
+			if test.isErrExpected && err == nil {
+				t.Fatalf("%q test failed expected error to occur but got nil", name)
+			}
+			if !test.isErrExpected && err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			if sender.sentData != test.expectedData {
+				t.Fatalf("%q test failed expected sent data %q but got %q", name, test.expectedData, sender.sentData)
+			}
+		})
+	}
+}
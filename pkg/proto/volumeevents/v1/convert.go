@@ -0,0 +1,108 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeeventsv1
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewObjectMeta converts Kubernetes object metadata into ObjectMeta
+func NewObjectMeta(objectMeta metav1.ObjectMeta) *ObjectMeta {
+	return &ObjectMeta{
+		Name:              objectMeta.Name,
+		Namespace:         objectMeta.Namespace,
+		Uid:               string(objectMeta.UID),
+		Labels:            objectMeta.Labels,
+		Annotations:       objectMeta.Annotations,
+		CreationTimestamp: newTimestamp(&objectMeta.CreationTimestamp),
+		DeletionTimestamp: newTimestamp(objectMeta.DeletionTimestamp),
+	}
+}
+
+// NewPersistentVolume converts Kubernetes PersistentVolume into PersistentVolume.
+// It returns nil if given object is nil
+func NewPersistentVolume(pvObj *corev1.PersistentVolume) *PersistentVolume {
+	if pvObj == nil {
+		return nil
+	}
+	pv := &PersistentVolume{
+		Metadata:         NewObjectMeta(pvObj.ObjectMeta),
+		StorageClassName: pvObj.Spec.StorageClassName,
+		AccessModes:      accessModesToStrings(pvObj.Spec.AccessModes),
+		ReclaimPolicy:    string(pvObj.Spec.PersistentVolumeReclaimPolicy),
+		Phase:            string(pvObj.Status.Phase),
+	}
+	if pvObj.Spec.VolumeMode != nil {
+		pv.VolumeMode = string(*pvObj.Spec.VolumeMode)
+	}
+	if capacity, isExist := pvObj.Spec.Capacity[corev1.ResourceStorage]; isExist {
+		pv.Capacity = capacity.String()
+	}
+	if pvObj.Spec.ClaimRef != nil {
+		pv.ClaimNamespace = pvObj.Spec.ClaimRef.Namespace
+		pv.ClaimName = pvObj.Spec.ClaimRef.Name
+	}
+	if pvObj.Spec.CSI != nil {
+		pv.CsiDriver = pvObj.Spec.CSI.Driver
+		pv.VolumeHandle = pvObj.Spec.CSI.VolumeHandle
+	}
+	return pv
+}
+
+// NewPersistentVolumeClaim converts Kubernetes PersistentVolumeClaim into
+// PersistentVolumeClaim. It returns nil if given object is nil
+func NewPersistentVolumeClaim(pvcObj *corev1.PersistentVolumeClaim) *PersistentVolumeClaim {
+	if pvcObj == nil {
+		return nil
+	}
+	pvc := &PersistentVolumeClaim{
+		Metadata:    NewObjectMeta(pvcObj.ObjectMeta),
+		AccessModes: accessModesToStrings(pvcObj.Spec.AccessModes),
+		VolumeName:  pvcObj.Spec.VolumeName,
+		Phase:       string(pvcObj.Status.Phase),
+	}
+	if pvcObj.Spec.StorageClassName != nil {
+		pvc.StorageClassName = *pvcObj.Spec.StorageClassName
+	}
+	if pvcObj.Spec.VolumeMode != nil {
+		pvc.VolumeMode = string(*pvcObj.Spec.VolumeMode)
+	}
+	if request, isExist := pvcObj.Spec.Resources.Requests[corev1.ResourceStorage]; isExist {
+		pvc.RequestedCapacity = request.String()
+	}
+	if capacity, isExist := pvcObj.Status.Capacity[corev1.ResourceStorage]; isExist {
+		pvc.Capacity = capacity.String()
+	}
+	return pvc
+}
+
+func newTimestamp(t *metav1.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+func accessModesToStrings(accessModes []corev1.PersistentVolumeAccessMode) []string {
+	var modes []string
+	for _, mode := range accessModes {
+		modes = append(modes, string(mode))
+	}
+	return modes
+}
@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package volumeeventsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// VolumeEventServiceClient is the client API for VolumeEventService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VolumeEventServiceClient interface {
+	// PublishVolumeEvent delivers a single volume event. Exporter treats
+	// the event as delivered only when the call returns successfully
+	PublishVolumeEvent(ctx context.Context, in *PublishVolumeEventRequest, opts ...grpc.CallOption) (*PublishVolumeEventResponse, error)
+}
+
+type volumeEventServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVolumeEventServiceClient(cc grpc.ClientConnInterface) VolumeEventServiceClient {
+	return &volumeEventServiceClient{cc}
+}
+
+func (c *volumeEventServiceClient) PublishVolumeEvent(ctx context.Context, in *PublishVolumeEventRequest, opts ...grpc.CallOption) (*PublishVolumeEventResponse, error) {
+	out := new(PublishVolumeEventResponse)
+	err := c.cc.Invoke(ctx, "/volumeevents.v1.VolumeEventService/PublishVolumeEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VolumeEventServiceServer is the server API for VolumeEventService service.
+// All implementations must embed UnimplementedVolumeEventServiceServer
+// for forward compatibility
+type VolumeEventServiceServer interface {
+	// PublishVolumeEvent delivers a single volume event. Exporter treats
+	// the event as delivered only when the call returns successfully
+	PublishVolumeEvent(context.Context, *PublishVolumeEventRequest) (*PublishVolumeEventResponse, error)
+	mustEmbedUnimplementedVolumeEventServiceServer()
+}
+
+// UnimplementedVolumeEventServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedVolumeEventServiceServer struct {
+}
+
+func (UnimplementedVolumeEventServiceServer) PublishVolumeEvent(context.Context, *PublishVolumeEventRequest) (*PublishVolumeEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishVolumeEvent not implemented")
+}
+func (UnimplementedVolumeEventServiceServer) mustEmbedUnimplementedVolumeEventServiceServer() {}
+
+// UnsafeVolumeEventServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VolumeEventServiceServer will
+// result in compilation errors.
+type UnsafeVolumeEventServiceServer interface {
+	mustEmbedUnimplementedVolumeEventServiceServer()
+}
+
+func RegisterVolumeEventServiceServer(s grpc.ServiceRegistrar, srv VolumeEventServiceServer) {
+	s.RegisterService(&VolumeEventService_ServiceDesc, srv)
+}
+
+func _VolumeEventService_PublishVolumeEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishVolumeEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeEventServiceServer).PublishVolumeEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/volumeevents.v1.VolumeEventService/PublishVolumeEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeEventServiceServer).PublishVolumeEvent(ctx, req.(*PublishVolumeEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VolumeEventService_ServiceDesc is the grpc.ServiceDesc for VolumeEventService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VolumeEventService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "volumeevents.v1.VolumeEventService",
+	HandlerType: (*VolumeEventServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PublishVolumeEvent",
+			Handler:    _VolumeEventService_PublishVolumeEvent_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "volumeevents/v1/volume_events.proto",
+}
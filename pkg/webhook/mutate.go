@@ -0,0 +1,175 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// casTypeLabelKey holds the CAS type of the volume
+	casTypeLabelKey = "openebs.io/cas-type"
+
+	// nfsEventFinalizer is the finalizer removed by NFS collector once
+	// delete event is exported
+	nfsEventFinalizer = "nfs." + collectorinterface.VolumeEventsFinalizer
+)
+
+// nfsServerResourceNameRegex matches the name of backing PVC, NFS server
+// deployment & service created by NFS provisioner i.e "nfs-"+<nfs-pv-name>
+// where NFS PV is named as "pvc-"+<nfs-pvc-uid>
+var nfsServerResourceNameRegex = regexp.MustCompile(
+	`^nfs-pvc-[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// patchOperation is an operation of JSON patch(RFC 6902)
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Mutator injects opt-in annotation and event finalizers into NFS
+// volumes and their backing resources at the time of creation
+type Mutator struct {
+	// storageClasses of NFS volumes to be mutated, all
+	// StorageClasses are matched if it is empty
+	storageClasses map[string]bool
+	// casTypes of NFS volumes to be mutated
+	casTypes map[string]bool
+	// nfsServerNamespaces holds the namespaces of backing
+	// PVC, NFS server deployment & service
+	nfsServerNamespaces map[string]bool
+}
+
+// NewMutator returns the mutator which matches volumes of given
+// StorageClasses & CAS types and NFS server resources of given namespaces
+func NewMutator(storageClasses, casTypes, nfsServerNamespaces []string) *Mutator {
+	return &Mutator{
+		storageClasses:      toSet(storageClasses),
+		casTypes:            toSet(casTypes),
+		nfsServerNamespaces: toSet(nfsServerNamespaces),
+	}
+}
+
+// Mutate returns the JSON patch for the object in admission request.
+// It returns nil if object doesn't require any mutation
+func (m *Mutator) Mutate(request *admissionv1.AdmissionRequest) ([]byte, error) {
+	if request.Operation != admissionv1.Create {
+		return nil, nil
+	}
+
+	var patch []patchOperation
+	switch request.Kind.Kind {
+	case "PersistentVolume":
+		pvObj := &corev1.PersistentVolume{}
+		if err := json.Unmarshal(request.Object.Raw, pvObj); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode PV %s", request.Name)
+		}
+		if m.isNFSVolume(pvObj) {
+			patch = getMetadataPatch(pvObj.ObjectMeta,
+				map[string]string{collectorinterface.VolumeEventsRequiredAnnotation: "true"}, nfsEventFinalizer)
+		} else if m.isBackingVolume(pvObj) {
+			patch = getMetadataPatch(pvObj.ObjectMeta, nil, nfsEventFinalizer)
+		}
+	case "PersistentVolumeClaim", "Deployment", "Service":
+		obj := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s %s/%s", request.Kind.Kind, request.Namespace, request.Name)
+		}
+		if m.isNFSServerResource(request.Namespace, obj.Name) {
+			patch = getMetadataPatch(obj.ObjectMeta, nil, nfsEventFinalizer)
+		}
+	}
+	if len(patch) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(patch)
+}
+
+// isNFSVolume returns true if volume is of matching CAS type and StorageClass
+func (m *Mutator) isNFSVolume(pvObj *corev1.PersistentVolume) bool {
+	casType := pvObj.Labels[casTypeLabelKey]
+	if casType == "" && pvObj.Spec.CSI != nil {
+		casType = pvObj.Spec.CSI.VolumeAttributes[casTypeLabelKey]
+	}
+	if !m.casTypes[casType] {
+		return false
+	}
+	return len(m.storageClasses) == 0 || m.storageClasses[pvObj.Spec.StorageClassName]
+}
+
+// isBackingVolume returns true if volume is bound to backing PVC
+func (m *Mutator) isBackingVolume(pvObj *corev1.PersistentVolume) bool {
+	claimRef := pvObj.Spec.ClaimRef
+	return claimRef != nil && m.isNFSServerResource(claimRef.Namespace, claimRef.Name)
+}
+
+// isNFSServerResource returns true if resource is created by NFS
+// provisioner in one of NFS server namespaces. Other resources of
+// the namespaces are not matched since nothing removes the finalizer
+// from them
+func (m *Mutator) isNFSServerResource(namespace, name string) bool {
+	return m.nfsServerNamespaces[namespace] && nfsServerResourceNameRegex.MatchString(name)
+}
+
+// getMetadataPatch returns the operations to add given
+// annotations and finalizer if they don't exist
+func getMetadataPatch(objectMeta metav1.ObjectMeta, annotations map[string]string, finalizer string) []patchOperation {
+	var patch []patchOperation
+	if len(annotations) != 0 {
+		if objectMeta.Annotations == nil {
+			patch = append(patch, patchOperation{Op: "add", Path: "/metadata/annotations", Value: annotations})
+		} else {
+			for key, value := range annotations {
+				if objectMeta.Annotations[key] == value {
+					continue
+				}
+				patch = append(patch, patchOperation{Op: "add", Path: "/metadata/annotations/" + escapeJSONPointer(key), Value: value})
+			}
+		}
+	}
+	for _, existingFinalizer := range objectMeta.Finalizers {
+		if existingFinalizer == finalizer {
+			return patch
+		}
+	}
+	if objectMeta.Finalizers == nil {
+		return append(patch, patchOperation{Op: "add", Path: "/metadata/finalizers", Value: []string{finalizer}})
+	}
+	return append(patch, patchOperation{Op: "add", Path: "/metadata/finalizers/-", Value: finalizer})
+}
+
+// escapeJSONPointer escapes the reference token of JSON pointer(RFC 6901)
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
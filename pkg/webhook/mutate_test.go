@@ -0,0 +1,235 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newAdmissionRequest(t *testing.T, kind, namespace string, operation admissionv1.Operation, obj interface{}) *admissionv1.AdmissionRequest {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal object: %v", err)
+	}
+	return &admissionv1.AdmissionRequest{
+		UID:       "uid-1",
+		Kind:      metav1.GroupVersionKind{Kind: kind},
+		Namespace: namespace,
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+func TestMutate(t *testing.T) {
+	mutator := NewMutator([]string{"openebs-rwx"}, []string{"nfs-kernel"}, []string{"openebs", "nfs-servers"})
+	tests := map[string]struct {
+		kind          string
+		namespace     string
+		operation     admissionv1.Operation
+		obj           interface{}
+		expectedPatch []patchOperation
+	}{
+		"when NFS volume of matching StorageClass is created": {
+			kind:      "PersistentVolume",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "pvc-1",
+					Labels: map[string]string{"openebs.io/cas-type": "nfs-kernel"},
+				},
+				Spec: corev1.PersistentVolumeSpec{StorageClassName: "openebs-rwx"},
+			},
+			expectedPatch: []patchOperation{
+				{Op: "add", Path: "/metadata/annotations", Value: map[string]interface{}{"events.openebs.io/required": "true"}},
+				{Op: "add", Path: "/metadata/finalizers", Value: []interface{}{"nfs.events.openebs.io/finalizer"}},
+			},
+		},
+		"when NFS volume already has annotations and finalizers": {
+			kind:      "PersistentVolume",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pvc-2",
+					Labels:      map[string]string{"openebs.io/cas-type": "nfs-kernel"},
+					Annotations: map[string]string{"pv.kubernetes.io/provisioned-by": "openebs.io/nfsrwx"},
+					Finalizers:  []string{"kubernetes.io/pv-protection"},
+				},
+				Spec: corev1.PersistentVolumeSpec{StorageClassName: "openebs-rwx"},
+			},
+			expectedPatch: []patchOperation{
+				{Op: "add", Path: "/metadata/annotations/events.openebs.io~1required", Value: "true"},
+				{Op: "add", Path: "/metadata/finalizers/-", Value: "nfs.events.openebs.io/finalizer"},
+			},
+		},
+		"when NFS volume is already mutated": {
+			kind:      "PersistentVolume",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pvc-3",
+					Labels:      map[string]string{"openebs.io/cas-type": "nfs-kernel"},
+					Annotations: map[string]string{"events.openebs.io/required": "true"},
+					Finalizers:  []string{"nfs.events.openebs.io/finalizer"},
+				},
+				Spec: corev1.PersistentVolumeSpec{StorageClassName: "openebs-rwx"},
+			},
+		},
+		"when NFS volume of other StorageClass is created": {
+			kind:      "PersistentVolume",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "pvc-4",
+					Labels: map[string]string{"openebs.io/cas-type": "nfs-kernel"},
+				},
+				Spec: corev1.PersistentVolumeSpec{StorageClassName: "openebs-rwx-dev"},
+			},
+		},
+		"when backing volume is created": {
+			kind:      "PersistentVolume",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc-5"},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: "openebs-hostpath",
+					ClaimRef:         &corev1.ObjectReference{Namespace: "nfs-servers", Name: "nfs-pvc-0b7d6f2e-4c4c-4a5e-9a83-1c3b2d6e7f80"},
+				},
+			},
+			expectedPatch: []patchOperation{
+				{Op: "add", Path: "/metadata/finalizers", Value: []interface{}{"nfs.events.openebs.io/finalizer"}},
+			},
+		},
+		"when backing PVC is created": {
+			kind:      "PersistentVolumeClaim",
+			namespace: "openebs",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "nfs-pvc-0b7d6f2e-4c4c-4a5e-9a83-1c3b2d6e7f80"},
+			},
+			expectedPatch: []patchOperation{
+				{Op: "add", Path: "/metadata/finalizers", Value: []interface{}{"nfs.events.openebs.io/finalizer"}},
+			},
+		},
+		"when NFS server deployment is created": {
+			kind:      "Deployment",
+			namespace: "openebs",
+			operation: admissionv1.Create,
+			obj: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "nfs-pvc-0b7d6f2e-4c4c-4a5e-9a83-1c3b2d6e7f80"},
+			},
+			expectedPatch: []patchOperation{
+				{Op: "add", Path: "/metadata/finalizers", Value: []interface{}{"nfs.events.openebs.io/finalizer"}},
+			},
+		},
+		"when NFS server service is created": {
+			kind:      "Service",
+			namespace: "nfs-servers",
+			operation: admissionv1.Create,
+			obj: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "nfs-pvc-0b7d6f2e-4c4c-4a5e-9a83-1c3b2d6e7f80"},
+			},
+			expectedPatch: []patchOperation{
+				{Op: "add", Path: "/metadata/finalizers", Value: []interface{}{"nfs.events.openebs.io/finalizer"}},
+			},
+		},
+		"when other service with nfs- prefix is created in NFS server namespace": {
+			kind:      "Service",
+			namespace: "openebs",
+			operation: admissionv1.Create,
+			obj: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "nfs-provisioner"},
+			},
+		},
+		"when other PVC with nfs-pvc- prefix is created in NFS server namespace": {
+			kind:      "PersistentVolumeClaim",
+			namespace: "openebs",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "nfs-pvc-data"},
+			},
+		},
+		"when volume bound to other PVC of NFS server namespace is created": {
+			kind:      "PersistentVolume",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc-7"},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: "openebs-hostpath",
+					ClaimRef:         &corev1.ObjectReference{Namespace: "openebs", Name: "nfs-backup"},
+				},
+			},
+		},
+		"when PVC is created in application namespace": {
+			kind:      "PersistentVolumeClaim",
+			namespace: "default",
+			operation: admissionv1.Create,
+			obj: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "nfs-data"},
+			},
+		},
+		"when NFS volume is updated": {
+			kind:      "PersistentVolume",
+			operation: admissionv1.Update,
+			obj: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "pvc-6",
+					Labels: map[string]string{"openebs.io/cas-type": "nfs-kernel"},
+				},
+				Spec: corev1.PersistentVolumeSpec{StorageClassName: "openebs-rwx"},
+			},
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			patch, err := mutator.Mutate(newAdmissionRequest(t, test.kind, test.namespace, test.operation, test.obj))
+			if err != nil {
+				t.Fatalf("%q test failed expected error not to occur but got %v", name, err)
+			}
+			var gotPatch []patchOperation
+			if patch != nil {
+				if err := json.Unmarshal(patch, &gotPatch); err != nil {
+					t.Fatalf("%q test failed expected valid JSON patch but got %v", name, err)
+				}
+			}
+			if !cmp.Equal(gotPatch, test.expectedPatch) {
+				t.Errorf("%q test failed expected no diff but got \n%s", name, cmp.Diff(test.expectedPatch, gotPatch))
+			}
+		})
+	}
+}
+
+func TestMutateInvalidObject(t *testing.T) {
+	mutator := NewMutator(nil, []string{"nfs-kernel"}, []string{"openebs"})
+	request := &admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Kind: "PersistentVolume"},
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: []byte("{invalid")},
+	}
+	if _, err := mutator.Mutate(request); err == nil {
+		t.Fatalf("expected error to occur for invalid object but got nil")
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/mayadata-io/volume-events-exporter/pkg/env"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// MutatePath is the path on which mutating webhook is served
+	MutatePath = "/mutate"
+
+	// maxRequestBodySize is the maximum size of admission review
+	maxRequestBodySize = 3 * 1024 * 1024
+
+	shutdownTimeout = 10 * time.Second
+)
+
+// Server serves the mutating admission webhook over TLS
+type Server struct {
+	server   *http.Server
+	certFile string
+	keyFile  string
+}
+
+// NewServer returns the webhook server configured via environment variables
+func NewServer() *Server {
+	mutator := NewMutator(
+		env.GetWebhookStorageClasses(),
+		env.GetWebhookCASTypes(),
+		env.GetWebhookNFSServerNamespaces())
+	return newServer(env.GetWebhookBindAddress(), env.GetWebhookTLSCertFile(), env.GetWebhookTLSKeyFile(), mutator)
+}
+
+func newServer(address, certFile, keyFile string, mutator *Mutator) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(MutatePath, &mutateHandler{mutator: mutator})
+	return &Server{
+		server: &http.Server{
+			Addr:    address,
+			Handler: mux,
+		},
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+}
+
+// Run serves the webhook till given context is cancelled
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		klog.Infof("Starting mutating webhook server on %s", s.server.Addr)
+		errCh <- s.server.ListenAndServeTLS(s.certFile, s.keyFile)
+	}()
+
+	select {
+	case err := <-errCh:
+		return errors.Wrapf(err, "failed to serve mutating webhook")
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	klog.Info("Shutting down mutating webhook server")
+	return s.server.Shutdown(shutdownCtx)
+}
+
+type mutateHandler struct {
+	mutator *Mutator
+}
+
+func (h *mutateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxRequestBodySize))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.review(review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("Failed to write admission review response: %v", err)
+	}
+}
+
+// review returns the admission response of given request. Objects which
+// fails to decode are rejected since they can't be exported reliably
+func (h *mutateHandler) review(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	response := &admissionv1.AdmissionResponse{
+		UID:     request.UID,
+		Allowed: true,
+	}
+	patch, err := h.mutator.Mutate(request)
+	if err != nil {
+		klog.Errorf("Failed to mutate %s %s/%s: %v", request.Kind.Kind, request.Namespace, request.Name, err)
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonBadRequest,
+			Code:    http.StatusBadRequest,
+		}
+		return response
+	}
+	if patch != nil {
+		patchType := admissionv1.PatchTypeJSONPatch
+		response.Patch = patch
+		response.PatchType = &patchType
+		klog.V(4).Infof("Mutated %s %s/%s with events finalizer", request.Kind.Kind, request.Namespace, request.Name)
+	}
+	return response
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMutateHandler(t *testing.T) {
+	handler := &mutateHandler{mutator: NewMutator(nil, []string{"nfs-kernel"}, []string{"openebs"})}
+	nfsPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "pvc-1",
+			Labels: map[string]string{"openebs.io/cas-type": "nfs-kernel"},
+		},
+	}
+	tests := map[string]struct {
+		method             string
+		body               []byte
+		expectedStatusCode int
+		isPatchExpected    bool
+	}{
+		"when NFS volume is reviewed": {
+			method: http.MethodPost,
+			body: func() []byte {
+				review := &admissionv1.AdmissionReview{
+					TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+					Request:  newAdmissionRequest(t, "PersistentVolume", "", admissionv1.Create, nfsPV),
+				}
+				data, _ := json.Marshal(review)
+				return data
+			}(),
+			expectedStatusCode: http.StatusOK,
+			isPatchExpected:    true,
+		},
+		"when request doesn't hold admission request": {
+			method:             http.MethodPost,
+			body:               []byte(`{"kind":"AdmissionReview"}`),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"when method is not POST": {
+			method:             http.MethodGet,
+			expectedStatusCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for name, test := range tests {
+		name := name
+		test := test
+		t.Run(name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(test.method, MutatePath, bytes.NewReader(test.body)))
+			if recorder.Code != test.expectedStatusCode {
+				t.Fatalf("%q test failed expected status code %d but got %d", name, test.expectedStatusCode, recorder.Code)
+			}
+			if test.expectedStatusCode != http.StatusOK {
+				return
+			}
+			review := &admissionv1.AdmissionReview{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), review); err != nil {
+				t.Fatalf("%q test failed expected valid admission review but got %v", name, err)
+			}
+			response := review.Response
+			if response == nil || response.UID != "uid-1" || !response.Allowed {
+				t.Fatalf("%q test failed expected allowed response of request uid-1 but got %v", name, response)
+			}
+			if test.isPatchExpected && (len(response.Patch) == 0 || response.PatchType == nil || *response.PatchType != admissionv1.PatchTypeJSONPatch) {
+				t.Errorf("%q test failed expected JSON patch but got %v", name, response)
+			}
+		})
+	}
+}
@@ -41,9 +41,12 @@ const (
 	VolumeDeleteBackendPVKey  = "it.nfs.openebs.io/vd-backend-pv"
 )
 
+// NFSCreateDeleteVolumeData holds the volume data of either create or
+// delete event. Only the volume data is decoded since enrichment fields
+// are common to both events
 type NFSCreateDeleteVolumeData struct {
-	nfspv.NFSCreateVolumeData
-	nfspv.NFSDeleteVolumeData
+	VolumeProvisioned *nfspv.NFSVolumeData `json:"volume_provisioned"`
+	VolumeDeleted     *nfspv.NFSVolumeData `json:"volume_deleted"`
 }
 
 type NFS struct {
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"context"
+
+	volumeeventsv1 "github.com/mayadata-io/volume-events-exporter/pkg/proto/volumeevents/v1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// ProcessVolumeEvent will process the typed event received over gRPC and
+// add the same annotations on backend PVC as REST based ProcessData
+func (n *NFS) ProcessVolumeEvent(event *volumeeventsv1.VolumeEvent) error {
+	nfsVolume := event.GetNfsVolume()
+	isNFSPVExist := nfsVolume.GetNfsPv() != nil
+	isBackendPVCExist := nfsVolume.GetBackingPvc() != nil
+	isBackendPVExist := nfsVolume.GetBackingPv() != nil
+	// All(nfspv, backend pvc, backend pv) data should exist
+	if !isNFSPVExist || !isBackendPVCExist || !isBackendPVExist {
+		return errors.Errorf("expected to have NFS PV(%t), Backend PVC(%t) and Backend PV(%t) to exist", isNFSPVExist, isBackendPVCExist, isBackendPVExist)
+	}
+	nfsPVMeta := nfsVolume.GetNfsPv().GetMetadata()
+	backendPVCMeta := nfsVolume.GetBackingPvc().GetMetadata()
+	backendPVMeta := nfsVolume.GetBackingPv().GetMetadata()
+
+	if nfsPVMeta.GetCreationTimestamp() == nil {
+		return errors.Errorf("expected to have creation timestamp on NFS PV %s", nfsPVMeta.GetName())
+	}
+
+	testAnnotations := map[string]string{}
+	switch event.GetEventType() {
+	case volumeeventsv1.EventType_EVENT_TYPE_CREATE:
+		if nfsPVMeta.GetDeletionTimestamp() != nil {
+			return errors.Errorf("expected no to have deletion timestamp on NFS PV %s", nfsPVMeta.GetName())
+		}
+		if nfsPVCMeta := nfsVolume.GetNfsPvc().GetMetadata(); nfsPVCMeta != nil {
+			testAnnotations[VolumeCreateNFSPVCKey] = nfsPVCMeta.GetNamespace() + "-" + nfsPVCMeta.GetName()
+		}
+		testAnnotations[VolumeCreateNFSPVKey] = nfsPVMeta.GetName()
+		testAnnotations[VolumeCreateBackendPVCKey] = backendPVCMeta.GetNamespace() + "-" + backendPVCMeta.GetName()
+		testAnnotations[VolumeCreateBackendPVKey] = backendPVMeta.GetName()
+	case volumeeventsv1.EventType_EVENT_TYPE_DELETE:
+		if nfsPVMeta.GetDeletionTimestamp() == nil {
+			return errors.Errorf("expected to have deletion timestamp on NFS PV %s", nfsPVMeta.GetName())
+		}
+		testAnnotations[VolumeDeleteNFSPVKey] = nfsPVMeta.GetName()
+		testAnnotations[VolumeDeleteBackendPVCKey] = backendPVCMeta.GetNamespace() + "-" + backendPVCMeta.GetName()
+		testAnnotations[VolumeDeleteBackendPVKey] = backendPVMeta.GetName()
+	default:
+		return errors.Errorf("unsupported event type %s", event.GetEventType())
+	}
+
+	backendPVC, err := n.Clientset.CoreV1().
+		PersistentVolumeClaims(backendPVCMeta.GetNamespace()).
+		Get(context.TODO(), backendPVCMeta.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if backendPVC.Annotations == nil {
+		backendPVC.Annotations = map[string]string{}
+	}
+	for key, value := range testAnnotations {
+		backendPVC.Annotations[key] = value
+	}
+	_, err = n.Clientset.CoreV1().
+		PersistentVolumeClaims(backendPVC.Namespace).
+		Update(context.TODO(), backendPVC, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	klog.Infof("Addedd annotations %v on backend pvc %s/%s for %s event", testAnnotations, backendPVC.Namespace, backendPVC.Name, event.GetEventType())
+	return nil
+}
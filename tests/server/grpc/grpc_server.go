@@ -0,0 +1,148 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/mayadata-io/volume-events-exporter/pkg/collectorinterface/grpcclient"
+	volumeeventsv1 "github.com/mayadata-io/volume-events-exporter/pkg/proto/volumeevents/v1"
+	"github.com/mayadata-io/volume-events-exporter/tests/server"
+	"github.com/pkg/errors"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// ServerConfig will instantiate the new gRPC server
+type ServerConfig struct {
+	// IPAddress holds address of the gRPC server
+	IPAddress string
+	// Port holds the port number on which server needs to run
+	Port int
+	// SecreteKey defines the secret key to communicate with the server
+	SecretKey string
+	// TLSTimeout defines the timeout expiry timeout of generated token
+	TLSTimeout time.Duration
+	// EventsReceiver to process events
+	EventsReceiver server.VolumeEventsReceiver
+}
+
+type grpcServer struct {
+	volumeeventsv1.UnimplementedVolumeEventServiceServer
+
+	address    string
+	secretKey  string
+	grpcServer *googlegrpc.Server
+	receiver   server.VolumeEventsReceiver
+	// token will be generated and stored in-memory and it can be used
+	// if integration test case required to interact with server
+	token string
+}
+
+func NewGRPCServer(config ServerConfig) (server.ServerInterface, error) {
+	token, err := server.NewToken(config.SecretKey, config.TLSTimeout)
+	if err != nil {
+		return nil, err
+	}
+	g := &grpcServer{
+		address:   config.IPAddress + ":" + strconv.Itoa(config.Port),
+		secretKey: config.SecretKey,
+		receiver:  config.EventsReceiver,
+		token:     token,
+	}
+	g.grpcServer = googlegrpc.NewServer(googlegrpc.UnaryInterceptor(g.isAuthorized))
+	volumeeventsv1.RegisterVolumeEventServiceServer(g.grpcServer, g)
+	return g, nil
+}
+
+// Start will start the gRPC service in a Go routine
+// NOTE: Stop must be called after shuting down the node
+func (g *grpcServer) Start() error {
+	listener, err := net.Listen("tcp", g.address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", g.address)
+	}
+	go func() {
+		err := g.grpcServer.Serve(listener)
+		if err != nil {
+			klog.Warningf("error: %s", err.Error())
+			return
+		}
+	}()
+	return nil
+}
+
+// Stop will stop the running service gracefully
+func (g *grpcServer) Stop() error {
+	g.grpcServer.GracefulStop()
+	return nil
+}
+
+// GetToken will return the token required that is required
+// to interact with the server
+func (g *grpcServer) GetToken() string {
+	return g.token
+}
+
+func (g *grpcServer) GetEventsReceiverEndpoint() string {
+	return g.address
+}
+
+// PublishVolumeEvent hands over the received event to events receiver
+func (g *grpcServer) PublishVolumeEvent(ctx context.Context,
+	req *volumeeventsv1.PublishVolumeEventRequest) (*volumeeventsv1.PublishVolumeEventResponse, error) {
+	klog.Infof("Received %s event of volume %s to process data", req.GetEvent().GetEventType(), req.GetEvent().GetPvName())
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	err := g.receiver.ProcessVolumeEvent(req.GetEvent())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to process data: %v", err)
+	}
+	return &volumeeventsv1.PublishVolumeEventResponse{}, nil
+}
+
+// isAuthorized is a token based authentication interceptor which verifies
+// whether received token is valid or not. If it is valid token then call
+// will be handled else error will be returned to the client
+func (g *grpcServer) isAuthorized(ctx context.Context,
+	req interface{},
+	info *googlegrpc.UnaryServerInfo,
+	handler googlegrpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(grpcclient.TokenMetadataKey)
+	if len(tokens) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "UnAuthorized!! Access Denied")
+	}
+	token, err := jwt.Parse(tokens[0], func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.Errorf("Unable to authorize")
+		}
+		return []byte(g.secretKey), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, status.Errorf(codes.Unauthenticated, "Failed to parse token error: %v", err)
+	}
+	return handler(ctx, req)
+}
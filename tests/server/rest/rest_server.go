@@ -23,10 +23,8 @@ import (
 
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gorilla/mux"
 	"github.com/mayadata-io/volume-events-exporter/tests/server"
-	"github.com/pkg/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
@@ -61,7 +59,7 @@ type restServer struct {
 }
 
 func NewRestServer(config ServerConfig) (server.ServerInterface, error) {
-	token, err := getToken(config.SecretKey, config.TLSTimeout)
+	token, err := server.NewToken(config.SecretKey, config.TLSTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -117,17 +115,3 @@ func (r *restServer) GetToken() string {
 func (r *restServer) GetEventsReceiverEndpoint() string {
 	return "http://" + r.service.httpServer.Addr + "/event-server"
 }
-
-func getToken(secretKey string, tlsTimeout time.Duration) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-
-	claims["authorized"] = true
-	claims["user"] = "rest service"
-	claims["exp"] = time.Now().Add(tlsTimeout).Unix()
-	tokenString, err := token.SignedString([]byte(secretKey))
-	if err != nil {
-		return "", errors.Wrapf(err, "something went wrong")
-	}
-	return tokenString, nil
-}
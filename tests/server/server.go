@@ -16,7 +16,11 @@ limitations under the License.
 
 package server
 
-import "net/http"
+import (
+	"net/http"
+
+	volumeeventsv1 "github.com/mayadata-io/volume-events-exporter/pkg/proto/volumeevents/v1"
+)
 
 // ServerInterface holds methods which are required to operate server
 // ex: gRPC, REST
@@ -43,3 +47,9 @@ type ServerInterface interface {
 type EventsReceiver interface {
 	ProcessData(req *http.Request) error
 }
+
+// VolumeEventsReceiver holds a method which will be triggered upon
+// receiving a typed volume event from the client(over gRPC)
+type VolumeEventsReceiver interface {
+	ProcessVolumeEvent(event *volumeeventsv1.VolumeEvent) error
+}
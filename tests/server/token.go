@@ -0,0 +1,39 @@
+/*
+Copyright © 2021 The MayaData Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+// NewToken generates a token signed with given secret key which
+// expires after tlsTimeout
+func NewToken(secretKey string, tlsTimeout time.Duration) (string, error) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["authorized"] = true
+	claims["user"] = "rest service"
+	claims["exp"] = time.Now().Add(tlsTimeout).Unix()
+	tokenString, err := token.SignedString([]byte(secretKey))
+	if err != nil {
+		return "", errors.Wrapf(err, "something went wrong")
+	}
+	return tokenString, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/mayadata-io/volume-events-exporter/tests/nfs"
 	"github.com/mayadata-io/volume-events-exporter/tests/server"
+	"github.com/mayadata-io/volume-events-exporter/tests/server/grpc"
 	"github.com/mayadata-io/volume-events-exporter/tests/server/rest"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -83,7 +84,7 @@ func init() {
 	flag.StringVar(&kubeConfigPath, "kubeconfig", os.Getenv("KUBECONFIG"), "path to kubeconfig to invoke kubernetes API calls")
 	flag.StringVar(&ipAddress, "address", "", "address on which server(event listener) will start. Defaults to machine IP Address")
 	flag.IntVar(&port, "port", 9090, "port on which server will listen. Defaults to 9090")
-	flag.StringVar(&serverType, "type", "rest", "type of the server to serve service. Supported only REST")
+	flag.StringVar(&serverType, "type", "rest", "type of the server to serve service. Supported rest and grpc")
 }
 
 var _ = BeforeSuite(func() {
@@ -146,6 +147,16 @@ func newServer(address string, port int, serverType string) (server.ServerInterf
 				Clientset: Client.Interface,
 			},
 		})
+	case "grpc":
+		return grpc.NewGRPCServer(grpc.ServerConfig{
+			IPAddress:  address,
+			Port:       port,
+			SecretKey:  "mayadata-io-secret",
+			TLSTimeout: 2 * time.Hour,
+			EventsReceiver: &nfs.NFS{
+				Clientset: Client.Interface,
+			},
+		})
 	}
 	return nil, errors.Errorf("Unsupported server type %s", serverType)
 }
@@ -170,16 +181,9 @@ func addEventControllerSideCar(deploymentNamespace, deploymentName string) error
 				Name:  "OPENEBS_IO_NFS_SERVER_NS",
 				Value: OpenEBSNamespace,
 			},
-			{
-				Name:  "CALLBACK_URL",
-				Value: serverIface.GetEventsReceiverEndpoint(),
-			},
-			{
-				Name:  "CALLBACK_TOKEN",
-				Value: serverIface.GetToken(),
-			},
 		},
 	}
+	volumeEventsCollector.Env = append(volumeEventsCollector.Env, getSinkEnv()...)
 
 	for idx, container := range deployObj.Spec.Template.Spec.Containers {
 		if container.Name == volumeEventsCollector.Name {
@@ -198,6 +202,37 @@ func addEventControllerSideCar(deploymentNamespace, deploymentName string) error
 	return Client.waitForDeploymentRollout(updatedDeployObj.Namespace, updatedDeployObj.Name)
 }
 
+// getSinkEnv returns the environment variables required by
+// volume-events-exporter to send events to the test server
+func getSinkEnv() []corev1.EnvVar {
+	if serverType == "grpc" {
+		return []corev1.EnvVar{
+			{
+				Name:  "EVENTS_SINK_TYPE",
+				Value: "grpc",
+			},
+			{
+				Name:  "GRPC_SERVER_ADDRESS",
+				Value: serverIface.GetEventsReceiverEndpoint(),
+			},
+			{
+				Name:  "GRPC_AUTH_TOKEN",
+				Value: serverIface.GetToken(),
+			},
+		}
+	}
+	return []corev1.EnvVar{
+		{
+			Name:  "CALLBACK_URL",
+			Value: serverIface.GetEventsReceiverEndpoint(),
+		},
+		{
+			Name:  "CALLBACK_TOKEN",
+			Value: serverIface.GetToken(),
+		},
+	}
+}
+
 func removeEventsCollectorSidecar(deploymentNamespace, deploymentName string) error {
 	var isVolumeEventsCollectorExist bool
 	var index int